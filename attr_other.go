@@ -0,0 +1,21 @@
+//go:build !windows
+
+package tasker
+
+import "errors"
+
+// archiveBitSupported reports whether the platform exposes the archive file attribute.
+const archiveBitSupported = false
+
+// errArchiveBitUnsupported is returned by archive attribute helpers outside Windows.
+var errArchiveBitUnsupported = errors.New("archive attribute is only available on Windows")
+
+// archiveBitSet is unavailable outside Windows.
+func archiveBitSet(path string) (bool, error) {
+	return false, errArchiveBitUnsupported
+}
+
+// setArchiveBit is unavailable outside Windows.
+func setArchiveBit(path string, set bool) error {
+	return errArchiveBitUnsupported
+}
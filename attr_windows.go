@@ -0,0 +1,39 @@
+//go:build windows
+
+package tasker
+
+import "syscall"
+
+// archiveBitSupported reports whether the platform exposes the archive file attribute.
+const archiveBitSupported = true
+
+// archiveBitSet reports whether the archive attribute is set, meaning the file changed since the last backup.
+func archiveBitSet(path string) (bool, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return false, err
+	}
+	attrs, err := syscall.GetFileAttributes(p)
+	if err != nil {
+		return false, err
+	}
+	return attrs&syscall.FILE_ATTRIBUTE_ARCHIVE != 0, nil
+}
+
+// setArchiveBit sets or clears the archive attribute, leaving all other attributes untouched.
+func setArchiveBit(path string, set bool) error {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return err
+	}
+	attrs, err := syscall.GetFileAttributes(p)
+	if err != nil {
+		return err
+	}
+	if set {
+		attrs |= syscall.FILE_ATTRIBUTE_ARCHIVE
+	} else {
+		attrs &^= syscall.FILE_ATTRIBUTE_ARCHIVE
+	}
+	return syscall.SetFileAttributes(p, attrs)
+}
@@ -0,0 +1,41 @@
+package tasker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// parseArchiveBitState parses the "set" or "clear" argument of the archive attribute flags.
+func parseArchiveBitState(s string) (bool, error) {
+	switch s {
+	case "set":
+		return true, nil
+	case "clear":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid archive bit state %q, expected set or clear", s)
+}
+
+// SetArchiveBits updates the archive attribute of every matching file instead of deleting it.
+func (fd *FileDeleter) SetArchiveBits(dirPath string, files []os.DirEntry, set bool) error {
+	var errors []string
+	for _, file := range files {
+		if !fd.matches(dirPath, file) {
+			continue
+		}
+
+		filePath := filepath.Join(dirPath, file.Name())
+		if err := setArchiveBit(filePath, set); err != nil {
+			errors = append(errors, fmt.Sprintf("%s, %v", filePath, err))
+			continue
+		}
+		fmt.Printf("Updated archive bit: %s\n", filePath)
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("errors occurred while updating attributes: %s", strings.Join(errors, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package tasker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupChecksumStateFile caches checksums of backup copies between runs.
+const backupChecksumStateFile = "backup-checksums.json"
+
+// backupChecksum is a cached checksum, valid while the backup copy keeps its size and modification time.
+type backupChecksum struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	SHA256  string    `json:"sha256"`
+}
+
+// BackupVerifier accepts only files with a byte-identical copy of the same name in BackupDir.
+// The backup directory is listed once per run, candidates are verified by a dedicated worker pool,
+// and checksums of backup copies are cached in the state directory so that repeated runs over slow
+// links only read backup copies that changed.
+type BackupVerifier struct {
+	BackupDir string
+	Workers   int
+	Form      NameForm
+
+	mu       sync.Mutex
+	listing  map[string]os.FileInfo
+	checksum map[string]backupChecksum
+	verified map[string]bool
+	dirty    bool
+}
+
+// NewBackupVerifier lists the backup directory and loads the checksum cache.
+func NewBackupVerifier(backupDir string, workers int, form NameForm) (*BackupVerifier, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	bv := &BackupVerifier{
+		BackupDir: backupDir,
+		Workers:   workers,
+		Form:      form,
+		listing:   make(map[string]os.FileInfo),
+		checksum:  make(map[string]backupChecksum),
+		verified:  make(map[string]bool),
+	}
+
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		bv.listing[form.normalize(entry.Name())] = info
+	}
+
+	if err := readState(backupChecksumStateFile, &bv.checksum); err != nil {
+		return nil, err
+	}
+	return bv, nil
+}
+
+// Prepare verifies every file with the given extension in parallel, ahead of the deletion pass.
+func (bv *BackupVerifier) Prepare(dirPath string, files []os.DirEntry, extension string) {
+	paths := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < bv.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				ok, err := bv.verify(path)
+				if err != nil {
+					// Leave the file unverified; Filter retries it and reports the error.
+					continue
+				}
+				bv.mu.Lock()
+				bv.verified[path] = ok
+				bv.mu.Unlock()
+			}
+		}()
+	}
+
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(bv.Form.normalize(file.Name()), bv.Form.normalize(extension)) {
+			paths <- filepath.Join(dirPath, file.Name())
+		}
+	}
+	close(paths)
+	wg.Wait()
+}
+
+// Filter accepts files whose backup copy is identical, using results from Prepare when available.
+func (bv *BackupVerifier) Filter(path string) (bool, error) {
+	bv.mu.Lock()
+	ok, done := bv.verified[path]
+	bv.mu.Unlock()
+	if !done {
+		var err error
+		if ok, err = bv.verify(path); err != nil {
+			return false, err
+		}
+	}
+
+	if !ok {
+		if _, exists := bv.listing[bv.Form.normalize(filepath.Base(path))]; exists {
+			fmt.Printf("Backup copy differs, keeping file: %s\n", path)
+		} else {
+			fmt.Printf("No backup copy, keeping file: %s\n", path)
+		}
+	}
+	return ok, nil
+}
+
+// Save persists the checksum cache if it changed, dropping entries for backup copies that are gone.
+func (bv *BackupVerifier) Save() error {
+	bv.mu.Lock()
+	defer bv.mu.Unlock()
+
+	for backupPath := range bv.checksum {
+		if filepath.Dir(backupPath) != filepath.Clean(bv.BackupDir) {
+			continue
+		}
+		if _, ok := bv.listing[bv.Form.normalize(filepath.Base(backupPath))]; !ok {
+			delete(bv.checksum, backupPath)
+			bv.dirty = true
+		}
+	}
+
+	if !bv.dirty {
+		return nil
+	}
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	return writeState(backupChecksumStateFile, bv.checksum)
+}
+
+// verify compares a file with its backup copy by size, then by SHA-256.
+func (bv *BackupVerifier) verify(path string) (bool, error) {
+	backupInfo, ok := bv.listing[bv.Form.normalize(filepath.Base(path))]
+	if !ok {
+		return false, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	if info.Size() != backupInfo.Size() {
+		return false, nil
+	}
+
+	backupSum, err := bv.backupChecksum(filepath.Join(bv.BackupDir, backupInfo.Name()), backupInfo)
+	if err != nil {
+		return false, err
+	}
+	sum, err := fileChecksum(path)
+	if err != nil {
+		return false, err
+	}
+	return sum == backupSum, nil
+}
+
+// backupChecksum returns the checksum of a backup copy, from the cache when the copy is unchanged.
+func (bv *BackupVerifier) backupChecksum(backupPath string, info os.FileInfo) (string, error) {
+	bv.mu.Lock()
+	cached, ok := bv.checksum[backupPath]
+	bv.mu.Unlock()
+	if ok && cached.Size == info.Size() && cached.ModTime.Equal(info.ModTime()) {
+		return cached.SHA256, nil
+	}
+
+	sum, err := fileChecksum(backupPath)
+	if err != nil {
+		return "", err
+	}
+
+	bv.mu.Lock()
+	bv.checksum[backupPath] = backupChecksum{Size: info.Size(), ModTime: info.ModTime(), SHA256: sum}
+	bv.dirty = true
+	bv.mu.Unlock()
+	return sum, nil
+}
+
+// fileChecksum returns the hex-encoded SHA-256 checksum of a file's contents.
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
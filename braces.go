@@ -0,0 +1,51 @@
+package tasker
+
+// ExpandBraces expands shell-style alternations such as /srv/{app1,app2}/logs into every combination.
+// Groups may be nested; braces without a comma are kept literally.
+func ExpandBraces(pattern string) []string {
+	start, end, parts := findBraceGroup(pattern)
+	if start < 0 {
+		return []string{pattern}
+	}
+
+	var expanded []string
+	for _, part := range parts {
+		expanded = append(expanded, ExpandBraces(pattern[:start]+part+pattern[end+1:])...)
+	}
+	return expanded
+}
+
+// findBraceGroup locates the first brace group containing a top-level comma and returns its bounds
+// and alternatives, or -1 when there is none.
+func findBraceGroup(s string) (int, int, []string) {
+	for start := 0; start < len(s); start++ {
+		if s[start] != '{' {
+			continue
+		}
+
+		depth := 0
+		last := start + 1
+		var parts []string
+	scan:
+		for i := start; i < len(s); i++ {
+			switch s[i] {
+			case '{':
+				depth++
+			case ',':
+				if depth == 1 {
+					parts = append(parts, s[last:i])
+					last = i + 1
+				}
+			case '}':
+				depth--
+				if depth == 0 {
+					if parts == nil {
+						break scan
+					}
+					return start, i, append(parts, s[last:i])
+				}
+			}
+		}
+	}
+	return -1, -1, nil
+}
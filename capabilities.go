@@ -0,0 +1,64 @@
+package tasker
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// Capability is an optional platform feature and whether this host can use it.
+type Capability struct {
+	Name      string
+	Available bool
+	Detail    string
+}
+
+// DetectCapabilities probes the optional platform features at run time. Platform-specific code sits
+// behind build tags and reports an error where a feature is missing, so the probes simply exercise
+// it against harmless targets and fall back gracefully.
+func DetectCapabilities() []Capability {
+	var caps []Capability
+	add := func(name string, err error) {
+		c := Capability{Name: name, Available: err == nil}
+		if err != nil {
+			c.Detail = err.Error()
+		}
+		caps = append(caps, c)
+	}
+
+	probe := os.TempDir()
+
+	_, err := archiveBitSet(probe)
+	add("archive attribute (-archive-bit, -archive-action, -require-backup)", err)
+
+	if info, statErr := os.Stat(probe); statErr != nil {
+		add("file ownership (-owner-quota)", statErr)
+	} else {
+		_, err = fileOwner(info)
+		add("file ownership (-owner-quota)", err)
+	}
+
+	_, err = isMountPoint(probe)
+	add("mount point detection (-expect-mount)", err)
+
+	_, err = systemLoad()
+	add("load average (-max-load)", err)
+
+	niceErr, ioIdleErr := probePriority()
+	add("CPU priority (-nice)", niceErr)
+	add("idle IO priority (-io-idle)", ioIdleErr)
+
+	return caps
+}
+
+// Capabilities prints which optional platform features this build can use on the current host.
+func (app *Application) Capabilities(args []string) {
+	fmt.Printf("Platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	for _, c := range DetectCapabilities() {
+		if c.Available {
+			fmt.Printf("  [yes] %s\n", c.Name)
+		} else {
+			fmt.Printf("  [no]  %s: %s\n", c.Name, c.Detail)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package tasker
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode"
+)
+
+// Case handling modes accepted by -case.
+const (
+	caseAuto        = "auto"
+	caseSensitive   = "sensitive"
+	caseInsensitive = "insensitive"
+)
+
+// parseCaseMode validates the value of -case.
+func parseCaseMode(s string) (string, error) {
+	switch mode := strings.ToLower(s); mode {
+	case caseAuto, caseSensitive, caseInsensitive:
+		return mode, nil
+	}
+	return "", fmt.Errorf("-case must be auto, sensitive or insensitive, got %q", s)
+}
+
+// caseInsensitiveDir reports whether names in dirPath are looked up case-insensitively, as on NTFS,
+// APFS and most SMB shares. It looks up an existing entry under its case-swapped name, so nothing
+// is written to the directory. Without a suitable entry it assumes the platform default.
+func caseInsensitiveDir(dirPath string) (bool, error) {
+	dir, err := os.Open(dirPath)
+	if err != nil {
+		return false, err
+	}
+	defer dir.Close()
+
+	for checked := 0; checked < 1024; {
+		names, err := dir.Readdirnames(128)
+		for _, name := range names {
+			checked++
+			swapped := swapCase(name)
+			if swapped == name {
+				continue
+			}
+			info, err := os.Lstat(filepath.Join(dirPath, name))
+			if err != nil {
+				continue
+			}
+			other, err := os.Lstat(filepath.Join(dirPath, swapped))
+			if os.IsNotExist(err) {
+				return false, nil
+			}
+			if err != nil {
+				continue
+			}
+			// Both spellings may exist as separate files on a case-sensitive filesystem.
+			return os.SameFile(info, other), nil
+		}
+		if err == io.EOF || len(names) == 0 {
+			break
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	return runtime.GOOS == "windows" || runtime.GOOS == "darwin", nil
+}
+
+// swapCase inverts the case of every letter in s.
+func swapCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsUpper(r) {
+			return unicode.ToLower(r)
+		}
+		return unicode.ToUpper(r)
+	}, s)
+}
@@ -0,0 +1,26 @@
+// Command file_delete_tasker deletes files with a given extension from a directory.
+package main
+
+import (
+	"os"
+	_ "time/tzdata" // Windows has no zoneinfo database for -tz
+
+	tasker "github.com/nilsonmart/file_delete_tasker"
+)
+
+func main() {
+	extension := tasker.DefaultExtension
+	if env := os.Getenv("TASKER_EXTENSION"); env != "" {
+		extension = env
+	}
+
+	validator := &tasker.DirectoryValidator{}
+	deleter := &tasker.FileDeleter{Extension: extension}
+	app := &tasker.Application{
+		Validator: validator,
+		Deleter:   deleter,
+	}
+
+	args := os.Args[1:] // Skip the executable path
+	app.Run(args)
+}
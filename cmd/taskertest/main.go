@@ -0,0 +1,103 @@
+// Command taskertest generates randomized directory trees for exercising the tasker end to end
+// and checks the tree against the expected outcome after a run.
+//
+//	taskertest generate -files 10000 -seed 42 /tmp/tree
+//	file_delete_tasker /tmp/tree
+//	taskertest check /tmp/tree
+//
+// The expectations describe a run with only -ext set; options that filter further, such as
+// -suspect-newer or -owner-quota, delete a subset of the files marked deleted. Files in use are
+// only emulated by the integration tests, through the engine's FS.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nilsonmart/file_delete_tasker/internal/testenv"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "generate":
+		err = generateCommand(os.Args[2:])
+	case "check":
+		err = checkCommand(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Println("Usage: taskertest generate [options] <directory_path>")
+	fmt.Println("       taskertest check <directory_path>")
+}
+
+func generateCommand(args []string) error {
+	opts := testenv.DefaultOptions()
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.IntVar(&opts.Files, "files", opts.Files, "number of `entries` to generate")
+	flags.Int64Var(&opts.Seed, "seed", opts.Seed, "random `seed`; the same seed generates the same tree")
+	flags.StringVar(&opts.Extension, "ext", opts.Extension, "file `extension` the tasker will be run with")
+	flags.Float64Var(&opts.Match, "match", opts.Match, "`share` of files with the -ext extension")
+	flags.Int64Var(&opts.MaxSize, "max-size", opts.MaxSize, "largest file size in `bytes`")
+	flags.DurationVar(&opts.MaxAge, "max-age", opts.MaxAge, "oldest file modification `age`")
+	flags.Float64Var(&opts.Symlinks, "symlinks", opts.Symlinks, "`share` of entries that are symbolic links")
+	flags.Float64Var(&opts.Dirs, "dirs", opts.Dirs, "`share` of entries that are subdirectories, some named with -ext")
+	flags.BoolVar(&opts.Lock, "lock", false, "leave a stale .tasker-lock file in the directory, to exercise -lock")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		usage()
+		return errors.New("expected one directory")
+	}
+
+	manifest, err := testenv.Generate(flags.Arg(0), opts)
+	if err != nil {
+		return err
+	}
+	deleted := 0
+	for _, e := range manifest.Entries {
+		if e.Delete {
+			deleted++
+		}
+	}
+	fmt.Printf("Generated %d entries in %s (seed %d), %d to be deleted\n", len(manifest.Entries), flags.Arg(0), manifest.Seed, deleted)
+	return nil
+}
+
+func checkCommand(args []string) error {
+	if len(args) != 1 {
+		usage()
+		return errors.New("expected one directory")
+	}
+	manifest, err := testenv.Load(args[0])
+	if err != nil {
+		return err
+	}
+	problems, err := manifest.Check()
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d entries do not match the expected outcome", len(problems))
+	}
+	fmt.Println("All entries match the expected outcome")
+	return nil
+}
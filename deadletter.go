@@ -0,0 +1,35 @@
+package tasker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteDeadLetter writes the absolute paths of files that could not be deleted to path, one per
+// line or, with nul, NUL-terminated for names containing newlines (as read by xargs -0). The file
+// is written even when nothing failed, so a follow-up run never picks up stale paths. It returns
+// the number of paths written.
+func WriteDeadLetter(path string, stats []*RunStats, nul bool) (int, error) {
+	sep := "\n"
+	if nul {
+		sep = "\x00"
+	}
+
+	var b strings.Builder
+	count := 0
+	for _, s := range stats {
+		if s == nil {
+			continue
+		}
+		for _, failed := range s.FailedPaths() {
+			if abs, err := filepath.Abs(failed); err == nil {
+				failed = abs
+			}
+			b.WriteString(failed)
+			b.WriteString(sep)
+			count++
+		}
+	}
+	return count, os.WriteFile(path, []byte(b.String()), 0o644)
+}
@@ -0,0 +1,154 @@
+package tasker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxClockSkew is how far a filesystem's timestamps may drift from the local clock before age-based
+// decisions become unreliable.
+const maxClockSkew = 5 * time.Minute
+
+// doctorCheck is the outcome of one environment check, with a suggested fix when it failed.
+type doctorCheck struct {
+	Name string
+	OK   bool
+	Info string
+	Fix  string
+
+	// NotApplicable marks optional features that are missing but only matter when used.
+	NotApplicable bool
+}
+
+// Doctor checks the environment the tool runs in and prints actionable fixes for any problem found.
+// Target roots given as arguments are checked for permissions and clock skew as well.
+func (app *Application) Doctor(args []string) {
+	var checks []doctorCheck
+
+	if ext := os.Getenv("TASKER_EXTENSION"); ext != "" && !strings.HasPrefix(ext, ".") {
+		checks = append(checks, doctorCheck{
+			Name: "default extension",
+			Info: fmt.Sprintf("TASKER_EXTENSION=%q has no leading dot and will match any name ending in it", ext),
+			Fix:  "set TASKER_EXTENSION to a value such as .rdp",
+		})
+	} else {
+		checks = append(checks, doctorCheck{Name: "default extension", OK: true, Info: app.Deleter.Extension})
+	}
+
+	checks = append(checks, checkStateDir())
+
+	if now := time.Now(); now.Year() < 2020 {
+		checks = append(checks, doctorCheck{
+			Name: "system clock",
+			Info: "local time is " + now.Format(time.RFC3339),
+			Fix:  "synchronise the system clock (NTP) before running age-based cleanups",
+		})
+	} else {
+		checks = append(checks, doctorCheck{Name: "system clock", OK: true, Info: now.Format(time.RFC3339)})
+	}
+
+	for _, arg := range args {
+		dirPath, err := ExpandPath(arg, time.Now())
+		if err != nil {
+			checks = append(checks, doctorCheck{Name: "target " + arg, Info: err.Error(), Fix: "fix the path template"})
+			continue
+		}
+		for _, root := range ExpandBraces(dirPath) {
+			checks = append(checks, checkTargetRoot(root)...)
+		}
+	}
+
+	for _, c := range DetectCapabilities() {
+		checks = append(checks, doctorCheck{Name: c.Name, OK: true, Info: c.Detail, NotApplicable: !c.Available})
+	}
+
+	problems := 0
+	for _, c := range checks {
+		status := "ok"
+		if c.NotApplicable {
+			status = "n/a"
+		}
+		if !c.OK {
+			status = "FAIL"
+			problems++
+		}
+		fmt.Printf("[%s] %s", status, c.Name)
+		if c.Info != "" {
+			fmt.Printf(": %s", c.Info)
+		}
+		fmt.Println()
+		if !c.OK && c.Fix != "" {
+			fmt.Printf("       fix: %s\n", c.Fix)
+		}
+	}
+
+	if problems > 0 {
+		fmt.Printf("%d problem(s) found.\n", problems)
+	} else {
+		fmt.Println("No problems found.")
+	}
+}
+
+// checkStateDir verifies the state directory exists and accepts atomic writes.
+func checkStateDir() doctorCheck {
+	check := doctorCheck{Name: "state directory"}
+	dir, err := StateDir()
+	if err != nil {
+		check.Info = err.Error()
+		check.Fix = "set " + stateDirEnv + " to a writable directory"
+		return check
+	}
+
+	probe := filepath.Join(dir, ".doctor-probe")
+	if err := writeFileAtomic(probe, []byte("ok"), 0o600); err != nil {
+		check.Info = fmt.Sprintf("%s is not writable: %v", dir, err)
+		check.Fix = "fix the directory permissions or set " + stateDirEnv + " to a writable directory"
+		return check
+	}
+	os.Remove(probe)
+
+	check.OK = true
+	check.Info = dir
+	return check
+}
+
+// checkTargetRoot verifies list and delete permissions on a target root and compares the
+// filesystem's clock with the local one, which matters for network shares.
+func checkTargetRoot(root string) []doctorCheck {
+	report := CheckPermissions(root)
+	perm := doctorCheck{Name: "permissions on " + root, OK: !report.NeedsElevation()}
+	if !perm.OK {
+		perm.Info = report.Err.Error()
+		if !report.CanList {
+			perm.Fix = "check the path exists and grant the running account read access"
+		} else {
+			perm.Fix = "grant the running account write access to the directory or run elevated"
+		}
+		return []doctorCheck{perm}
+	}
+
+	skew := doctorCheck{Name: "clock skew on " + root}
+	probe, err := os.CreateTemp(root, ".tasker-probe-*")
+	if err != nil {
+		skew.Info = err.Error()
+		return []doctorCheck{perm, skew}
+	}
+	info, err := probe.Stat()
+	probe.Close()
+	os.Remove(probe.Name())
+	if err != nil {
+		skew.Info = err.Error()
+		return []doctorCheck{perm, skew}
+	}
+
+	drift := time.Since(info.ModTime()).Round(time.Second)
+	skew.Info = fmt.Sprintf("filesystem clock differs by %s", drift)
+	skew.OK = drift.Abs() <= maxClockSkew
+	if !skew.OK {
+		skew.Fix = "synchronise the clocks of this host and the file server"
+	}
+	return []doctorCheck{perm, skew}
+}
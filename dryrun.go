@@ -0,0 +1,74 @@
+package tasker
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// dryRunOnlyFile lists, one per line, path prefixes such as /finance/** under which the tasker
+// never deletes anything, whatever options a run is given. It is read from a fixed system-wide
+// location, /etc/file_delete_tasker on Unix and ProgramData\file_delete_tasker on Windows, that
+// neither the environment nor the user's state directory can move.
+const dryRunOnlyFile = "dry-run-only"
+
+// DryRunOnly holds the protected path prefixes.
+type DryRunOnly []string
+
+// LoadDryRunOnly reads the protected prefixes. A missing file protects nothing; blank lines and
+// # comments are ignored, and a trailing /** or /* is accepted for readability.
+func LoadDryRunOnly() (DryRunOnly, error) {
+	path, err := dryRunOnlyPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var prefixes DryRunOnly
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(filepath.ToSlash(line), "/**"), "/*")
+		prefixes = append(prefixes, canonicalPath(filepath.FromSlash(line)))
+	}
+	return prefixes, scanner.Err()
+}
+
+// Protects reports whether path lies under one of the protected prefixes.
+func (d DryRunOnly) Protects(path string) bool {
+	path = canonicalPath(path)
+	for _, prefix := range d {
+		if path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, string(filepath.Separator))+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalPath makes a path absolute and resolves symbolic links where it exists, so a protected
+// directory cannot be reached under another name. Windows paths compare case-insensitively.
+func canonicalPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if runtime.GOOS == "windows" {
+		path = strings.ToLower(path)
+	}
+	return path
+}
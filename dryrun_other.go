@@ -0,0 +1,8 @@
+//go:build !windows
+
+package tasker
+
+// dryRunOnlyPath is the system-wide list of dry-run-only prefixes.
+func dryRunOnlyPath() (string, error) {
+	return "/etc/file_delete_tasker/" + dryRunOnlyFile, nil
+}
@@ -0,0 +1,36 @@
+//go:build windows
+
+package tasker
+
+import (
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procSHGetKnownFolderPath = syscall.NewLazyDLL("shell32.dll").NewProc("SHGetKnownFolderPath")
+	procCoTaskMemFree        = syscall.NewLazyDLL("ole32.dll").NewProc("CoTaskMemFree")
+)
+
+// folderIDProgramData is FOLDERID_ProgramData, {62AB5D82-FDC1-4DC3-A9DD-070D1D495D97}.
+var folderIDProgramData = syscall.GUID{
+	Data1: 0x62AB5D82, Data2: 0xFDC1, Data3: 0x4DC3,
+	Data4: [8]byte{0xA9, 0xDD, 0x07, 0x0D, 0x1D, 0x49, 0x5D, 0x97},
+}
+
+// dryRunOnlyPath is the system-wide list of dry-run-only prefixes under ProgramData. The folder
+// is asked of the shell rather than read from %ProgramData%, which any user can change.
+func dryRunOnlyPath() (string, error) {
+	var p *uint16
+	if r, _, _ := procSHGetKnownFolderPath.Call(uintptr(unsafe.Pointer(&folderIDProgramData)), 0, 0, uintptr(unsafe.Pointer(&p))); r != 0 {
+		return "", syscall.Errno(r)
+	}
+	defer procCoTaskMemFree.Call(uintptr(unsafe.Pointer(p)))
+	n := 0
+	for *(*uint16)(unsafe.Add(unsafe.Pointer(p), n*2)) != 0 {
+		n++
+	}
+	dir := syscall.UTF16ToString(unsafe.Slice(p, n))
+	return filepath.Join(dir, "file_delete_tasker", dryRunOnlyFile), nil
+}
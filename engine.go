@@ -0,0 +1,209 @@
+package tasker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"time"
+)
+
+// Deletion defaults shared by the command line and engines built without options.
+const (
+	defaultWorkers = 5
+	defaultRetries = 3
+	defaultTimeout = time.Second
+)
+
+// FS is the filesystem an engine lists and deletes files in.
+type FS interface {
+	ReadDir(name string) ([]os.DirEntry, error)
+	Remove(name string) error
+}
+
+// osFS is the local filesystem.
+type osFS struct{}
+
+func (osFS) ReadDir(name string) ([]os.DirEntry, error) { return os.ReadDir(name) }
+func (osFS) Remove(name string) error                   { return os.Remove(name) }
+
+// Engine deletes matching files from directories for programs embedding the tasker. Its
+// configuration is fixed by NewEngine, so one engine may clean several directories at once.
+type Engine struct {
+	deleter FileDeleter
+	workers int
+	retries int
+	timeout time.Duration
+	hooks   hooks
+}
+
+// Option configures an Engine.
+type Option func(*Engine)
+
+// NewEngine creates an engine deleting files with the default extension using the given options.
+func NewEngine(opts ...Option) *Engine {
+	e := &Engine{
+		deleter: FileDeleter{Extension: DefaultExtension},
+		workers: defaultWorkers,
+		retries: defaultRetries,
+		timeout: defaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.deleter.hooks = &e.hooks
+	return e
+}
+
+// WithExtension sets the extension of the files to delete.
+func WithExtension(ext string) Option {
+	return func(e *Engine) { e.deleter.Extension = ext }
+}
+
+// WithWorkers sets the number of files deleted in parallel.
+func WithWorkers(n int) Option {
+	return func(e *Engine) {
+		if n > 0 {
+			e.workers = n
+		}
+	}
+}
+
+// WithRetry sets how often a failed deletion is retried and how long each attempt may take.
+// Negative retries and non-positive timeouts are ignored.
+func WithRetry(retries int, timeout time.Duration) Option {
+	return func(e *Engine) {
+		if retries >= 0 {
+			e.retries = retries
+		}
+		if timeout > 0 {
+			e.timeout = timeout
+		}
+	}
+}
+
+// WithMatcher adds a filter every file must pass before it is deleted. name explains kept files.
+func WithMatcher(name string, filter FileFilter) Option {
+	return func(e *Engine) { e.deleter.AddFilter(name, filter) }
+}
+
+// WithNameForm sets the Unicode normalization file names are compared in.
+func WithNameForm(form NameForm) Option {
+	return func(e *Engine) { e.deleter.NameForm = form }
+}
+
+// WithCaseFolding makes name matching case-insensitive, as suits NTFS, APFS and SMB shares.
+func WithCaseFolding(fold bool) Option {
+	return func(e *Engine) { e.deleter.FoldCase = fold }
+}
+
+// WithLogger sends progress messages to w instead of standard output.
+func WithLogger(w io.Writer) Option {
+	return func(e *Engine) { e.deleter.Log = w }
+}
+
+// WithFS lists and deletes files through fsys instead of the local filesystem.
+func WithFS(fsys FS) Option {
+	return func(e *Engine) { e.deleter.FS = fsys }
+}
+
+// Clean deletes the matching files in dirPath and returns the run's statistics. It is a shortcut
+// for Start followed by Wait.
+func (e *Engine) Clean(dirPath string) (RunRecord, error) {
+	r := e.Start(dirPath)
+	err := r.Wait()
+	return r.Stats(), err
+}
+
+// refuseDryRunOnly returns an error for a directory under a dry-run-only path. Programs embedding
+// the engine are held to the same boundary as the command line, which only plans there.
+func refuseDryRunOnly(dirPath string) error {
+	dryRunOnly, err := LoadDryRunOnly()
+	if err != nil {
+		return fmt.Errorf("reading dry-run-only paths: %v", err)
+	}
+	if dryRunOnly.Protects(dirPath) {
+		return fmt.Errorf("refusing to delete: %s is under a dry-run-only path", dirPath)
+	}
+	return nil
+}
+
+// Outcomes reported by Results.
+const (
+	ActionDeleted = "deleted"
+	ActionSkipped = "skipped"
+	ActionFailed  = "failed"
+)
+
+// Result is the outcome of a run for one directory entry.
+type Result struct {
+	Path   string
+	Action string
+	Size   int64  // bytes reclaimed, for deleted files
+	Reason string // why the entry was kept, for skipped entries
+}
+
+// Results cleans dirPath and yields the outcome of every entry as it happens, with the error for
+// failed deletions. Breaking out of the loop cancels the run: no further files are deleted, and
+// Results returns once deletions already in progress have finished. Callbacks registered on the
+// engine are still invoked.
+func (e *Engine) Results(dirPath string) iter.Seq2[Result, error] {
+	return func(yield func(Result, error) bool) {
+		if err := refuseDryRunOnly(dirPath); err != nil {
+			yield(Result{Path: dirPath, Action: ActionFailed}, err)
+			return
+		}
+		deleter := e.deleter.forRun(&RunStats{})
+		files, err := deleter.fs().ReadDir(dirPath)
+		if err != nil {
+			yield(Result{Path: dirPath, Action: ActionFailed}, fmt.Errorf("reading directory: %v", err))
+			return
+		}
+
+		type outcome struct {
+			result Result
+			err    error
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		outcomes := make(chan outcome)
+		send := func(r Result, err error) {
+			select {
+			case outcomes <- outcome{r, err}:
+			case <-ctx.Done():
+			}
+		}
+
+		// Forward outcomes to the loop body while still notifying the engine's own callbacks.
+		deleter.hooks = &hooks{
+			onMatch: e.hooks.match,
+			onDelete: func(path string, size int64) {
+				e.hooks.delete(path, size)
+				send(Result{Path: path, Action: ActionDeleted, Size: size}, nil)
+			},
+			onSkip: func(path, reason string) {
+				e.hooks.skip(path, reason)
+				send(Result{Path: path, Action: ActionSkipped, Reason: reason}, nil)
+			},
+			onError: func(path string, err error) {
+				e.hooks.fail(path, err)
+				send(Result{Path: path, Action: ActionFailed}, err)
+			},
+		}
+
+		go func() {
+			defer close(outcomes)
+			deleter.deleteFiles(ctx, dirPath, files, e.workers, e.retries, e.timeout)
+		}()
+
+		for o := range outcomes {
+			if !yield(o.result, o.err) {
+				cancel()
+				for range outcomes {
+				}
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package tasker_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	tasker "github.com/nilsonmart/file_delete_tasker"
+)
+
+// makeRoot creates a directory holding matching .rdp files and as many .txt files to keep.
+func makeRoot(t *testing.T, files int) string {
+	t.Helper()
+	dir := t.TempDir()
+	for i := 0; i < files; i++ {
+		for _, ext := range []string{".rdp", ".txt"} {
+			if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%03d%s", i, ext)), []byte("x"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return dir
+}
+
+// TestEngineParallelRoots cleans several directories through one engine at once, mixing Clean,
+// Start and Results, and is meant to be run with -race.
+func TestEngineParallelRoots(t *testing.T) {
+	const roots, files = 8, 50
+
+	engine := tasker.NewEngine(tasker.WithWorkers(4), tasker.WithLogger(io.Discard))
+	deleted := make(map[string]bool)
+	engine.OnDelete(func(path string, size int64) {
+		// Callbacks never run concurrently, so the map needs no lock of its own.
+		deleted[path] = true
+	})
+
+	dirs := make([]string, roots)
+	for i := range dirs {
+		dirs[i] = makeRoot(t, files)
+	}
+
+	var wg sync.WaitGroup
+	for i, dir := range dirs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var stats tasker.RunRecord
+			var err error
+			switch i % 3 {
+			case 0:
+				stats, err = engine.Clean(dir)
+			case 1:
+				run := engine.Start(dir)
+				for run.Phase() != tasker.PhaseDone {
+					run.Stats()
+					time.Sleep(time.Millisecond)
+				}
+				err = run.Wait()
+				stats = run.Stats()
+			case 2:
+				for r, rerr := range engine.Results(dir) {
+					if rerr != nil {
+						err = rerr
+					}
+					if r.Action == tasker.ActionDeleted {
+						stats.Deleted++
+					}
+				}
+			}
+			if err != nil {
+				t.Errorf("%s: %v", dir, err)
+			}
+			if stats.Deleted != files {
+				t.Errorf("%s: deleted %d files, want %d", dir, stats.Deleted, files)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(deleted) != roots*files {
+		t.Errorf("OnDelete saw %d files, want %d", len(deleted), roots*files)
+	}
+	for _, dir := range dirs {
+		left, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(left) != files {
+			t.Errorf("%s: %d entries left, want the %d .txt files", dir, len(left), files)
+		}
+		for _, e := range left {
+			if filepath.Ext(e.Name()) != ".txt" {
+				t.Errorf("%s: %s was not deleted", dir, e.Name())
+			}
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package tasker
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+)
+
+// Failure classes used to group retries and failed deletions.
+const (
+	classTimeout    = "timeout"
+	classPermission = "permission"
+	classInUse      = "in use"
+	classGone       = "gone"
+	classOther      = "other"
+)
+
+// errorClass groups a deletion error by its likely cause, so operators can tell a share that is
+// merely slow from files another program holds open or an account lacking rights.
+func errorClass(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return classTimeout
+	case errors.Is(err, fs.ErrPermission):
+		return classPermission
+	case errors.Is(err, fs.ErrNotExist):
+		return classGone
+	case fileInUse(err):
+		return classInUse
+	}
+	return classOther
+}
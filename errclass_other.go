@@ -0,0 +1,13 @@
+//go:build !windows
+
+package tasker
+
+import (
+	"errors"
+	"syscall"
+)
+
+// fileInUse reports whether err means the file or its mount is busy.
+func fileInUse(err error) bool {
+	return errors.Is(err, syscall.EBUSY) || errors.Is(err, syscall.ETXTBSY)
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package tasker
+
+import (
+	"errors"
+	"syscall"
+)
+
+// Windows error codes for files another process has open or locked.
+const (
+	errorSharingViolation syscall.Errno = 32
+	errorLockViolation    syscall.Errno = 33
+)
+
+// fileInUse reports whether err means another process holds the file open or locked.
+func fileInUse(err error) bool {
+	return errors.Is(err, errorSharingViolation) || errors.Is(err, errorLockViolation)
+}
@@ -0,0 +1,286 @@
+package tasker
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF decoder for image filters
+	_ "image/jpeg" // register JPEG decoder for image filters
+	_ "image/png"  // register PNG decoder for image filters
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileFilter decides whether a file that matches the extension should be deleted.
+type FileFilter func(path string) (bool, error)
+
+// ImageSmallerThan returns a filter accepting images whose width and height are both below the given bounds.
+func ImageSmallerThan(maxWidth, maxHeight int) FileFilter {
+	return func(path string) (bool, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return false, err
+		}
+		defer f.Close()
+
+		cfg, _, err := image.DecodeConfig(f)
+		if err != nil {
+			return false, fmt.Errorf("not a recognised image: %v", err)
+		}
+		return cfg.Width < maxWidth && cfg.Height < maxHeight, nil
+	}
+}
+
+// ArchiveContainsOnly returns a filter accepting zip and tar archives that are empty
+// or whose files all carry the given extension. Archives are read in place, never extracted.
+func ArchiveContainsOnly(extension string, form NameForm) FileFilter {
+	extension = form.normalize(extension)
+	return func(path string) (bool, error) {
+		names, err := archiveEntries(path)
+		if err != nil {
+			return false, err
+		}
+		for _, name := range names {
+			if extension == "" || !strings.HasSuffix(form.normalize(name), extension) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+}
+
+// archiveEntries lists the regular files stored in a zip, tar, or gzip-compressed tar archive.
+func archiveEntries(path string) ([]string, error) {
+	lower := strings.ToLower(path)
+	if strings.HasSuffix(lower, ".zip") {
+		r, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		var names []string
+		for _, f := range r.File {
+			if !f.FileInfo().IsDir() {
+				names = append(names, f.Name)
+			}
+		}
+		return names, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var src io.Reader = f
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		src = gz
+	case strings.HasSuffix(lower, ".tar"):
+	default:
+		return nil, fmt.Errorf("unsupported archive type")
+	}
+
+	var names []string
+	tr := tar.NewReader(src)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeDir {
+			names = append(names, hdr.Name)
+		}
+	}
+}
+
+// OrphanedSidecar returns a filter accepting sidecar files whose primary file is missing from the listing.
+// A primary for "movie.srt" is any other entry named "movie" or "movie.<ext>"; for "data.iso.md5" the
+// primary is "data.iso". Entries carrying the sidecar extension themselves never count as primaries.
+func OrphanedSidecar(files []os.DirEntry, sidecarExt string, key func(name string) string) FileFilter {
+	sidecarExt = key(sidecarExt)
+	stems := make(map[string]bool)
+	for _, file := range files {
+		name := key(file.Name())
+		if strings.HasSuffix(name, sidecarExt) {
+			continue
+		}
+		stems[name] = true
+		for i := len(name) - 1; i > 0; i-- {
+			if name[i] == '.' {
+				stems[name[:i]] = true
+			}
+		}
+	}
+
+	return func(path string) (bool, error) {
+		stem := strings.TrimSuffix(key(filepath.Base(path)), sidecarExt)
+		return !stems[stem], nil
+	}
+}
+
+// ArchiveBitIs returns a filter accepting files whose archive attribute matches the wanted state.
+func ArchiveBitIs(set bool) FileFilter {
+	return func(path string) (bool, error) {
+		current, err := archiveBitSet(path)
+		if err != nil {
+			return false, err
+		}
+		return current == set, nil
+	}
+}
+
+// MissingFrom returns a filter accepting files that have no counterpart of the same name in primaryDir,
+// turning a run against a mirror directory into a one-way prune sync.
+func MissingFrom(primaryDir string, form NameForm) FileFilter {
+	return func(path string) (bool, error) {
+		// The primary may spell the name in another normalization form than the mirror.
+		for _, name := range form.variants(filepath.Base(path)) {
+			_, err := os.Lstat(filepath.Join(primaryDir, name))
+			if !os.IsNotExist(err) {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+}
+
+// pngSignature is the fixed eight-byte header every PNG file starts with.
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// validityChecks maps lower-case extensions to a check reporting whether a file is damaged. Errors
+// reading the file are returned as errors rather than reported as damage.
+var validityChecks = map[string]func(path string) (bool, error){
+	".gz":  checkGzip,
+	".tgz": checkGzip,
+	".png": checkPNGHeader,
+	".zip": checkZip,
+}
+
+// BrokenFile is a filter accepting zero-byte files and files that fail a format-specific validity check,
+// such as truncated gzip streams left behind by crashed jobs. A file that cannot be read is skipped
+// with an error, since it may well be intact.
+func BrokenFile(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	if info.Size() == 0 {
+		return true, nil
+	}
+
+	check, ok := validityChecks[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return false, nil
+	}
+	return check(path)
+}
+
+// checkedFile remembers the first error reading the file itself, which tells an I/O problem apart
+// from a format error raised by a decoder.
+type checkedFile struct {
+	f   *os.File
+	err error
+}
+
+func (c *checkedFile) Read(p []byte) (int, error) {
+	n, err := c.f.Read(p)
+	c.record(err)
+	return n, err
+}
+
+func (c *checkedFile) ReadAt(p []byte, off int64) (int, error) {
+	n, err := c.f.ReadAt(p, off)
+	c.record(err)
+	return n, err
+}
+
+func (c *checkedFile) record(err error) {
+	if err != nil && err != io.EOF && c.err == nil {
+		c.err = err
+	}
+}
+
+// damaged turns a decoder error into the result of a validity check.
+func (c *checkedFile) damaged(err error) (bool, error) {
+	if c.err != nil {
+		return false, c.err
+	}
+	return err != nil, nil
+}
+
+// checkGzip decompresses the whole stream so truncation and checksum errors surface.
+func checkGzip(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	c := &checkedFile{f: f}
+
+	gz, err := gzip.NewReader(c)
+	if err != nil {
+		return c.damaged(err)
+	}
+	defer gz.Close()
+
+	_, err = io.Copy(io.Discard, gz)
+	return c.damaged(err)
+}
+
+// checkPNGHeader verifies the PNG signature.
+func checkPNGHeader(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	c := &checkedFile{f: f}
+
+	header := make([]byte, len(pngSignature))
+	if _, err := io.ReadFull(c, header); err != nil {
+		return c.damaged(err)
+	}
+	return !bytes.Equal(header, pngSignature), nil
+}
+
+// checkZip verifies the zip central directory can be read.
+func checkZip(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+	c := &checkedFile{f: f}
+
+	_, err = zip.NewReader(c, info.Size())
+	return c.damaged(err)
+}
+
+// parseDimensions parses a WIDTHxHEIGHT string such as "640x480".
+func parseDimensions(s string) (int, int, error) {
+	var width, height int
+	if _, err := fmt.Sscanf(s, "%dx%d", &width, &height); err != nil || width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("invalid dimensions %q, expected WIDTHxHEIGHT", s)
+	}
+	return width, height, nil
+}
@@ -0,0 +1,96 @@
+package tasker
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// gzipped returns a complete gzip stream holding data.
+func gzipped(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestBrokenFile(t *testing.T) {
+	dir := t.TempDir()
+	good := gzipped(t, "report contents")
+	files := map[string][]byte{
+		"good.gz":      good,
+		"truncated.gz": good[:len(good)-6],
+		"garbage.gz":   []byte("not gzip at all"),
+		"empty.txt":    nil,
+		"good.png":     append(append([]byte{}, pngSignature...), "IHDR"...),
+		"bad.png":      []byte("GIF89a..."),
+		"short.png":    pngSignature[:3],
+		"bad.zip":      []byte("PK not really"),
+		"plain.txt":    []byte("text"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"good.gz", false},
+		{"truncated.gz", true},
+		{"garbage.gz", true},
+		{"empty.txt", true},
+		{"good.png", false},
+		{"bad.png", true},
+		{"short.png", true},
+		{"bad.zip", true},
+		{"plain.txt", false},
+	}
+	for _, tt := range tests {
+		got, err := BrokenFile(filepath.Join(dir, tt.name))
+		if err != nil || got != tt.want {
+			t.Errorf("BrokenFile(%s) = %v, %v; want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestBrokenFileUnreadable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes do not deny reading on Windows")
+	}
+	if os.Geteuid() == 0 {
+		t.Skip("root reads files whatever their mode")
+	}
+	path := filepath.Join(t.TempDir(), "good.gz")
+	if err := os.WriteFile(path, gzipped(t, "report contents"), 0o000); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := BrokenFile(path); got || err == nil {
+		t.Errorf("BrokenFile on an unreadable file = %v, %v; want false and an error", got, err)
+	}
+}
+
+func TestBrokenFileReadError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("directories cannot be opened for reading on Windows")
+	}
+	// Opening a directory succeeds but reading it fails, which is not damage to a gzip stream.
+	path := filepath.Join(t.TempDir(), "backup.gz")
+	if err := os.Mkdir(path, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := BrokenFile(path); got || err == nil {
+		t.Errorf("BrokenFile on a read error = %v, %v; want false and an error", got, err)
+	}
+}
@@ -0,0 +1,145 @@
+package tasker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+)
+
+// fingerprintSlots is the number of MinHash slots kept per directory fingerprint.
+const fingerprintSlots = 64
+
+// DirectoryFingerprint summarises a directory's top-level entries. MinHash slots let two fingerprints
+// estimate how many names the listings share without storing the names themselves.
+type DirectoryFingerprint struct {
+	Count   int      `json:"count"`
+	MinHash []uint64 `json:"minhash"`
+}
+
+// NewDirectoryFingerprint computes the fingerprint of a directory listing.
+func NewDirectoryFingerprint(files []os.DirEntry) DirectoryFingerprint {
+	fp := DirectoryFingerprint{Count: len(files), MinHash: make([]uint64, fingerprintSlots)}
+	for i := range fp.MinHash {
+		fp.MinHash[i] = ^uint64(0)
+	}
+
+	for _, file := range files {
+		h := fnv.New64a()
+		h.Write([]byte(file.Name()))
+		base := h.Sum64()
+		for i := range fp.MinHash {
+			if v := mix64(base ^ uint64(i+1)*0x9e3779b97f4a7c15); v < fp.MinHash[i] {
+				fp.MinHash[i] = v
+			}
+		}
+	}
+	return fp
+}
+
+// mix64 is the splitmix64 finalizer, used to derive independent hash functions from one name hash.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// ChangeFrom estimates the fraction (0 to 1) of entries that differ from an earlier fingerprint.
+func (fp DirectoryFingerprint) ChangeFrom(prev DirectoryFingerprint) float64 {
+	if fp.Count == 0 && prev.Count == 0 {
+		return 0
+	}
+	if fp.Count == 0 || prev.Count == 0 || len(prev.MinHash) != len(fp.MinHash) {
+		return 1
+	}
+
+	same := 0
+	for i := range fp.MinHash {
+		if fp.MinHash[i] == prev.MinHash[i] {
+			same++
+		}
+	}
+	return 1 - float64(same)/float64(len(fp.MinHash))
+}
+
+// fingerprintStateFile is the state file holding fingerprints of every managed directory.
+const fingerprintStateFile = "fingerprints.json"
+
+// loadFingerprints reads the stored fingerprints keyed by absolute directory path. A damaged state
+// file is reported as a *corruptStateError and left in place, since losing the fingerprints would
+// let -max-change pass every directory as seen for the first time.
+func loadFingerprints() (map[string]DirectoryFingerprint, error) {
+	fingerprints := make(map[string]DirectoryFingerprint)
+	if err := readStateStrict(fingerprintStateFile, &fingerprints); err != nil {
+		return nil, err
+	}
+	return fingerprints, nil
+}
+
+// saveFingerprint records the fingerprint of dirPath, keeping those of other directories. It runs
+// only after a run went ahead, so a damaged state file is moved aside and started afresh here.
+func saveFingerprint(dirPath string, fp DirectoryFingerprint) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	fingerprints := make(map[string]DirectoryFingerprint)
+	if err := readState(fingerprintStateFile, &fingerprints); err != nil {
+		return err
+	}
+	fingerprints[dirPath] = fp
+	return writeState(fingerprintStateFile, fingerprints)
+}
+
+// isInteractive reports whether an operator is present on standard input. The null device is a
+// character device too, so it is ruled out explicitly for scheduler-launched runs.
+func isInteractive() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(info, null)
+}
+
+// confirmDirectoryChange compares a directory with its last recorded fingerprint and reports whether
+// the run may proceed. A change above maxPercent, or one that cannot be measured because the stored
+// fingerprints are damaged, needs accept or, when attended, an operator's consent.
+func confirmDirectoryChange(dirPath string, files []os.DirEntry, maxPercent float64, accept bool) (bool, error) {
+	fingerprints, err := loadFingerprints()
+	var corrupt *corruptStateError
+	switch {
+	case errors.As(err, &corrupt):
+		// The change cannot be measured, which must not pass for no change at all.
+		if accept {
+			return true, nil
+		}
+		fmt.Printf("Cannot check how much the directory changed since the last run: %v\n", err)
+	case err != nil:
+		return false, err
+	default:
+		prev, ok := fingerprints[dirPath]
+		if !ok {
+			return true, nil
+		}
+
+		change := NewDirectoryFingerprint(files).ChangeFrom(prev) * 100
+		if change <= maxPercent || accept {
+			return true, nil
+		}
+		fmt.Printf("Directory changed by about %.0f%% since the last run (%d entries then, %d now).\n", change, prev.Count, len(files))
+	}
+
+	if !isInteractive() {
+		fmt.Println("Refusing unattended run; rerun with -accept-changes after checking the directory.")
+		return false, nil
+	}
+
+	fmt.Println("Continue anyway? [y/N]")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.EqualFold(strings.TrimSpace(answer), "y"), nil
+}
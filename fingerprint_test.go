@@ -0,0 +1,37 @@
+package tasker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfirmDirectoryChangeCorruptState(t *testing.T) {
+	if isInteractive() {
+		t.Skip("standard input is a terminal, so a refused run would ask for consent")
+	}
+	dir := t.TempDir()
+	t.Setenv(stateDirEnv, dir)
+	path := filepath.Join(dir, fingerprintStateFile)
+	if err := os.WriteFile(path, []byte(`{"/data": {"count": 3, "minh`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		proceed, err := confirmDirectoryChange("/data", nil, 10, false)
+		if err != nil || proceed {
+			t.Fatalf("attempt %d: proceed %v, %v; want a refusal", i, proceed, err)
+		}
+	}
+	if proceed, err := confirmDirectoryChange("/data", nil, 10, true); err != nil || !proceed {
+		t.Fatalf("with accept: proceed %v, %v", proceed, err)
+	}
+
+	// Saving after an accepted run starts the fingerprints afresh.
+	if err := saveFingerprint("/data", NewDirectoryFingerprint(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if proceed, err := confirmDirectoryChange("/data", nil, 10, false); err != nil || !proceed {
+		t.Fatalf("after saving: proceed %v, %v", proceed, err)
+	}
+}
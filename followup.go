@@ -0,0 +1,103 @@
+package tasker
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FollowUp is an action taken on a file that still could not be deleted after all retries.
+type FollowUp interface {
+	Apply(path string) error
+	String() string
+}
+
+// FollowUps maps failure classes (see errorClass) to the action taken for files failing that
+// way. It is set from repeated -on-failure class=action flags.
+type FollowUps map[string]FollowUp
+
+// String lists the configured actions as class=action pairs.
+func (f FollowUps) String() string {
+	pairs := make([]string, 0, len(f))
+	for class, action := range f {
+		pairs = append(pairs, strings.ReplaceAll(class, " ", "-")+"="+action.String())
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Set adds one class=action pair. Classes may be written with a hyphen, e.g. in-use.
+func (f FollowUps) Set(s string) error {
+	class, action, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("expected class=action, got %q", s)
+	}
+	class = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(class)), "-", " ")
+	switch class {
+	case classTimeout, classPermission, classInUse, classOther:
+	default:
+		return fmt.Errorf("unknown failure class %q (use timeout, permission, in-use or other)", class)
+	}
+
+	followUp, err := parseFollowUp(strings.TrimSpace(action))
+	if err != nil {
+		return err
+	}
+	f[class] = followUp
+	return nil
+}
+
+// parseFollowUp parses the action part of -on-failure.
+func parseFollowUp(action string) (FollowUp, error) {
+	if file, ok := strings.CutPrefix(action, "queue:"); ok && file != "" {
+		return &queueFollowUp{file: file}, nil
+	}
+	if action == "reboot" {
+		if !rebootDeleteSupported {
+			return nil, fmt.Errorf("follow-up action reboot is only available on Windows")
+		}
+		return rebootFollowUp{}, nil
+	}
+	return nil, fmt.Errorf("unknown follow-up action %q (use queue:FILE or reboot)", action)
+}
+
+// run applies the follow-up configured for the class of cause, if any, reporting the outcome.
+func (f FollowUps) run(path string, cause error) {
+	class := errorClass(cause)
+	action, ok := f[class]
+	if !ok {
+		return
+	}
+	if err := action.Apply(path); err != nil {
+		fmt.Printf("Follow-up %s failed for %s: %v\n", action, path, err)
+		return
+	}
+	fmt.Printf("Follow-up for %s (%s): %s\n", path, class, action)
+}
+
+// queueFollowUp appends failed paths to a queue file for another tool or an elevated run.
+type queueFollowUp struct {
+	file string
+	mu   sync.Mutex
+}
+
+// Apply appends path as one line of the queue file.
+func (q *queueFollowUp) Apply(path string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	f, err := os.OpenFile(q.file, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(path + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (q *queueFollowUp) String() string {
+	return "queue:" + q.file
+}
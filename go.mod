@@ -1,3 +1,5 @@
 module github.com/nilsonmart/file_delete_tasker
 
 go 1.23
+
+require golang.org/x/text v0.21.0
@@ -0,0 +1,135 @@
+package tasker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// groupStem returns the basename shared by members of a file group, e.g. "report" for "report.csv".
+func groupStem(name string) string {
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// DeleteGroups deletes files sharing a basename with a matching file as one unit. Every member is moved
+// into a staging directory first; if any move fails, the members already staged are moved back.
+func (fd *FileDeleter) DeleteGroups(dirPath string, files []os.DirEntry) error {
+	groups := make(map[string][]os.DirEntry)
+	var order []string
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		stem := groupStem(fd.nameKey(file.Name()))
+		if _, ok := groups[stem]; !ok {
+			order = append(order, stem)
+		}
+		groups[stem] = append(groups[stem], file)
+	}
+
+	var errors []string
+	for _, stem := range order {
+		members := groups[stem]
+		// The stem is normalized for matching; messages use a member's name as it is on disk.
+		label := filepath.Join(dirPath, groupStem(members[0].Name()))
+		matched := make([]bool, len(members))
+		eligible := false
+		for i, member := range members {
+			if matched[i] = fd.matches(dirPath, member); matched[i] {
+				eligible = true
+			}
+		}
+		if !eligible {
+			continue
+		}
+
+		// Siblings go with the matching file, but only if each is as safe to delete on its own.
+		kept := ""
+		for i, member := range members {
+			if matched[i] {
+				continue
+			}
+			if reason := fd.safetyReason(dirPath, member); reason != "" {
+				kept = fmt.Sprintf("%s: %s", member.Name(), reason)
+				break
+			}
+		}
+		if kept != "" {
+			fd.logf("Kept group: %s (%s)\n", label, kept)
+			for _, member := range members {
+				fd.hooks.skip(filepath.Join(dirPath, member.Name()), "group member kept: "+kept)
+			}
+			continue
+		}
+
+		// Sizes are read up front; once staged, the entries no longer resolve.
+		sizes := make([]int64, len(members))
+		for i, member := range members {
+			if info, err := member.Info(); err == nil {
+				sizes[i] = info.Size()
+			}
+		}
+		gone, err := deleteGroup(dirPath, members)
+		for i, member := range members {
+			memberPath := filepath.Join(dirPath, member.Name())
+			switch {
+			case gone[member.Name()]:
+				fd.Stats.AddGone(memberPath)
+				fmt.Printf("Already gone: %s\n", memberPath)
+			case err != nil:
+				fd.Stats.AddFailed(memberPath)
+			default:
+				fd.Stats.AddDeleted(memberPath, sizes[i])
+			}
+		}
+		if err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+		fmt.Printf("Deleted group: %s (%d files)\n", label, len(members)-len(gone))
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("errors occurred during group deletion: %s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+// deleteGroup stages all members and removes them together, rolling back on the first failed move.
+// Members removed by someone else in the meantime are skipped and returned by name.
+func deleteGroup(dirPath string, members []os.DirEntry) (map[string]bool, error) {
+	// The staging directory lives inside dirPath so every move is a same-filesystem rename.
+	staging, err := os.MkdirTemp(dirPath, ".tasker-staging-")
+	if err != nil {
+		return nil, err
+	}
+
+	gone := make(map[string]bool)
+	var staged []string
+	for _, member := range members {
+		name := member.Name()
+		err := os.Rename(filepath.Join(dirPath, name), filepath.Join(staging, name))
+		if os.IsNotExist(err) {
+			gone[name] = true
+			continue
+		}
+		if err != nil {
+			var rollbackErrors []string
+			for _, moved := range staged {
+				if rerr := os.Rename(filepath.Join(staging, moved), filepath.Join(dirPath, moved)); rerr != nil {
+					rollbackErrors = append(rollbackErrors, rerr.Error())
+				}
+			}
+			if len(rollbackErrors) > 0 {
+				return gone, fmt.Errorf("failed to stage %s: %v; rollback incomplete, files remain in %s: %s",
+					name, err, staging, strings.Join(rollbackErrors, "; "))
+			}
+			os.Remove(staging)
+			return gone, fmt.Errorf("failed to stage %s, group rolled back: %v", name, err)
+		}
+		staged = append(staged, name)
+	}
+
+	return gone, os.RemoveAll(staging)
+}
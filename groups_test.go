@@ -0,0 +1,65 @@
+package tasker
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDeleteGroupsAppliesSafetyFiltersToEveryMember(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"r.rdp", "r.csv", "s.rdp", "s.csv", "t.csv"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fd := &FileDeleter{Extension: ".rdp", Log: io.Discard}
+	fd.AddSafetyFilter("unsafe", func(path string) (bool, error) {
+		return filepath.Base(path) != "r.csv", nil
+	})
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fd.DeleteGroups(dir, files); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]bool{"r.rdp": true, "r.csv": true, "s.rdp": false, "s.csv": false, "t.csv": true} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if exists := err == nil; exists != want {
+			t.Errorf("%s exists = %v, want %v", name, exists, want)
+		}
+	}
+}
+
+func TestDeleteGroupsSkipsVanishedMembers(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"r.rdp", "r.csv"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Another process removes a member after the listing.
+	if err := os.Remove(filepath.Join(dir, "r.csv")); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := &RunStats{}
+	fd := (&FileDeleter{Extension: ".rdp", Log: io.Discard}).forRun(stats)
+	if err := fd.DeleteGroups(dir, files); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "r.rdp")); !os.IsNotExist(err) {
+		t.Errorf("r.rdp was not deleted: %v", err)
+	}
+	if r := stats.Record(); r.Deleted != 1 || r.Gone != 1 || r.Failed != 0 {
+		t.Errorf("deleted %d, gone %d, failed %d; want 1, 1, 0", r.Deleted, r.Gone, r.Failed)
+	}
+}
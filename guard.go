@@ -0,0 +1,31 @@
+package tasker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// confirmDeletionShare reports whether a run may delete matched of the total files in dirPath. A
+// share above maxPercent, as when a selector unexpectedly matches everything, needs accept or,
+// when attended, an operator's consent.
+func confirmDeletionShare(dirPath string, matched, total int, maxPercent float64, accept bool) bool {
+	if total == 0 || accept {
+		return true
+	}
+	share := float64(matched) / float64(total) * 100
+	if share <= maxPercent {
+		return true
+	}
+
+	fmt.Printf("Run would delete %d of %d files (%.0f%%) in %s, more than -max-percent %g%%.\n", matched, total, share, dirPath, maxPercent)
+	if !isInteractive() {
+		fmt.Println("Refusing unattended run; rerun with -accept-changes after checking the selection.")
+		return false
+	}
+
+	fmt.Println("Continue anyway? [y/N]")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.EqualFold(strings.TrimSpace(answer), "y")
+}
@@ -0,0 +1,54 @@
+package tasker
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// healthcheckTimeout bounds each ping so an unreachable monitor never stalls a cleanup run.
+const healthcheckTimeout = 10 * time.Second
+
+// Healthcheck pings a monitoring URL when a run starts, succeeds, or fails, following the
+// healthchecks.io convention of /start and /fail suffixes. Services that only expect a success
+// ping, such as Dead Man's Snitch, simply ignore the others.
+type Healthcheck struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewHealthcheck creates a healthcheck for the given ping URL.
+func NewHealthcheck(url string) *Healthcheck {
+	return &Healthcheck{
+		URL:    strings.TrimSuffix(url, "/"),
+		Client: &http.Client{Timeout: healthcheckTimeout},
+	}
+}
+
+// Start signals that a run has begun.
+func (hc *Healthcheck) Start() {
+	hc.ping(hc.URL + "/start")
+}
+
+// Finish signals the outcome of a run.
+func (hc *Healthcheck) Finish(success bool) {
+	if success {
+		hc.ping(hc.URL)
+	} else {
+		hc.ping(hc.URL + "/fail")
+	}
+}
+
+// ping sends a single request, reporting but otherwise ignoring failures.
+func (hc *Healthcheck) ping(url string) {
+	resp, err := hc.Client.Get(url)
+	if err != nil {
+		fmt.Println("Error sending healthcheck ping:", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		fmt.Printf("Healthcheck ping to %s returned %s\n", url, resp.Status)
+	}
+}
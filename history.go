@@ -0,0 +1,135 @@
+package tasker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// historyStateFile is the state file holding the recent run records of every managed directory.
+const historyStateFile = "history.json"
+
+// historyRuns is how many run records are kept per directory; older ones are dropped.
+const historyRuns = 100
+
+// loadHistory reads the stored run records, oldest first, keyed by absolute directory path. Files
+// written when only the last record of a directory was kept are read as one-record histories.
+func loadHistory() (map[string][]RunRecord, error) {
+	var raw map[string]json.RawMessage
+	if err := readState(historyStateFile, &raw); err != nil {
+		return nil, err
+	}
+	history := make(map[string][]RunRecord, len(raw))
+	for dirPath, data := range raw {
+		var records []RunRecord
+		if err := json.Unmarshal(data, &records); err != nil {
+			var record RunRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return nil, fmt.Errorf("history of %s: %v", dirPath, err)
+			}
+			records = []RunRecord{record}
+		}
+		history[dirPath] = records
+	}
+	return history, nil
+}
+
+// recordRun appends the run record for dirPath to its history and returns the previous one, if any.
+// Failure streaks in record are extended by those of the previous run before it is stored.
+func recordRun(dirPath string, record *RunRecord) (RunRecord, bool, error) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	history, err := loadHistory()
+	if err != nil {
+		return RunRecord{}, false, err
+	}
+	records := history[dirPath]
+	var prev RunRecord
+	ok := len(records) > 0
+	if ok {
+		prev = records[len(records)-1]
+	}
+	for path := range record.FailureStreaks {
+		record.FailureStreaks[path] += prev.FailureStreaks[path]
+	}
+	records = append(records, *record)
+	if len(records) > historyRuns {
+		records = records[len(records)-historyRuns:]
+	}
+	history[dirPath] = records
+	return prev, ok, writeState(historyStateFile, history)
+}
+
+// printRunComparison shows the run's counters alongside their change since the previous run.
+func printRunComparison(record, prev RunRecord, hasPrev bool) {
+	if !hasPrev {
+		fmt.Printf("This run: deleted %d files, reclaimed %s, %d failures (no previous run recorded)\n",
+			record.Deleted, ByteSize(record.Bytes), record.Failed)
+		printGone(record)
+		printRetries(record)
+		return
+	}
+	fmt.Printf("This run: deleted %d files (%+d), reclaimed %s (%s), %d failures (%+d) compared with %s\n",
+		record.Deleted, record.Deleted-prev.Deleted,
+		ByteSize(record.Bytes), byteDelta(record.Bytes-prev.Bytes),
+		record.Failed, record.Failed-prev.Failed,
+		prev.Time.Format(time.RFC3339))
+	printGone(record)
+	printRetries(record)
+	if prev.Note != "" {
+		fmt.Printf("Previous run note: %s\n", prev.Note)
+	}
+}
+
+// byteDelta formats a signed change in bytes, e.g. "+1.5MB" or "-200B".
+func byteDelta(delta int64) string {
+	if delta < 0 {
+		return "-" + ByteSize(-delta).String()
+	}
+	return "+" + ByteSize(delta).String()
+}
+
+// printGone notes files that vanished before they could be deleted; they are not failures.
+func printGone(record RunRecord) {
+	if record.Gone > 0 {
+		fmt.Printf("%d matching files were already gone, removed by another process\n", record.Gone)
+	}
+}
+
+// printRetries summarises retried attempts by error class, e.g. "Retries: 4 in use, 1 timeout".
+func printRetries(record RunRecord) {
+	if len(record.Retries) == 0 {
+		return
+	}
+	classes := make([]string, 0, len(record.Retries))
+	for class := range record.Retries {
+		classes = append(classes, class)
+	}
+	sort.Slice(classes, func(i, j int) bool {
+		if record.Retries[classes[i]] != record.Retries[classes[j]] {
+			return record.Retries[classes[i]] > record.Retries[classes[j]]
+		}
+		return classes[i] < classes[j]
+	})
+	parts := make([]string, len(classes))
+	for i, class := range classes {
+		parts[i] = fmt.Sprintf("%d %s", record.Retries[class], class)
+	}
+	fmt.Printf("Retries: %s\n", strings.Join(parts, ", "))
+}
+
+// alertChronicFailures warns about files that have failed deletion in at least threshold consecutive runs.
+func alertChronicFailures(record RunRecord, threshold int) {
+	if threshold <= 0 {
+		return
+	}
+	for path, streak := range record.FailureStreaks {
+		if streak >= threshold {
+			fmt.Fprintf(os.Stderr, "ALERT: %s has failed deletion in %d consecutive runs\n", path, streak)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package tasker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordRunKeepsNotes(t *testing.T) {
+	t.Setenv(stateDirEnv, t.TempDir())
+
+	for i := 0; i < historyRuns+5; i++ {
+		record := RunRecord{Deleted: i, Note: fmt.Sprintf("run %d", i)}
+		prev, ok, err := recordRun("/data", &record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != (i > 0) || (ok && prev.Deleted != i-1) {
+			t.Fatalf("run %d: previous record %+v, %v", i, prev, ok)
+		}
+	}
+
+	history, err := loadHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := history["/data"]
+	if len(records) != historyRuns {
+		t.Fatalf("kept %d records, want %d", len(records), historyRuns)
+	}
+	for i, r := range records {
+		if want := fmt.Sprintf("run %d", i+5); r.Note != want {
+			t.Errorf("record %d has note %q, want %q", i, r.Note, want)
+		}
+	}
+}
+
+func TestLoadHistoryLegacy(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(stateDirEnv, dir)
+	legacy := `{"/data": {"deleted": 7, "note": "before the upgrade"}}`
+	if err := os.WriteFile(filepath.Join(dir, historyStateFile), []byte(legacy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := loadHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records := history["/data"]; len(records) != 1 || records[0].Deleted != 7 || records[0].Note != "before the upgrade" {
+		t.Errorf("legacy history read as %+v", records)
+	}
+}
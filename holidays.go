@@ -0,0 +1,75 @@
+package tasker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// HolidayCalendar is a set of dates on which runs are skipped, keyed as YYYY-MM-DD.
+type HolidayCalendar map[string]bool
+
+// LoadHolidays reads a holiday calendar. iCalendar files contribute the DTSTART date of every
+// event; any other file lists one YYYY-MM-DD date per line, optionally as a YAML list item
+// followed by a description. Blank lines and # comments are ignored.
+func LoadHolidays(path string) (HolidayCalendar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	holidays := make(HolidayCalendar)
+	ical := false
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.EqualFold(line, "BEGIN:VCALENDAR") {
+			ical = true
+		}
+
+		if ical {
+			// DTSTART;VALUE=DATE:20261225 or DTSTART:20261225T000000Z
+			if !strings.HasPrefix(strings.ToUpper(line), "DTSTART") {
+				continue
+			}
+			_, value, _ := strings.Cut(line, ":")
+			if len(value) < 8 {
+				return nil, fmt.Errorf("%s:%d: malformed DTSTART %q", path, lineNo, line)
+			}
+			date, err := time.Parse("20060102", value[:8])
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %v", path, lineNo, err)
+			}
+			holidays[date.Format(time.DateOnly)] = true
+			continue
+		}
+
+		// A YAML key such as "holidays:" introducing the list.
+		if strings.HasSuffix(line, ":") && !strings.ContainsAny(line, " -") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "- "))
+		field, _, _ := strings.Cut(line, " ")
+		field = strings.Trim(strings.TrimSuffix(field, ":"), `"'`)
+		date, err := time.Parse(time.DateOnly, field)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: expected a YYYY-MM-DD date, got %q", path, lineNo, line)
+		}
+		holidays[date.Format(time.DateOnly)] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+// Contains reports whether the calendar date of t is a holiday.
+func (h HolidayCalendar) Contains(t time.Time) bool {
+	return h[t.Format(time.DateOnly)]
+}
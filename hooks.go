@@ -0,0 +1,89 @@
+package tasker
+
+import "sync"
+
+// hooks holds the callbacks an embedding application registered on an Engine. Callbacks run on
+// the goroutine that matched or deleted the file, which may be a deletion worker, but never
+// concurrently with one another, even when the engine cleans several directories at once.
+// Deletions wait for a callback to return, so slow work belongs on the caller's goroutine.
+type hooks struct {
+	mu       sync.Mutex
+	onMatch  func(path string)
+	onDelete func(path string, size int64)
+	onSkip   func(path, reason string)
+	onError  func(path string, err error)
+}
+
+// OnMatch registers fn to be called for each file selected for deletion.
+func (e *Engine) OnMatch(fn func(path string)) {
+	e.hooks.mu.Lock()
+	defer e.hooks.mu.Unlock()
+	e.hooks.onMatch = fn
+}
+
+// OnDelete registers fn to be called after each file is deleted.
+func (e *Engine) OnDelete(fn func(path string, size int64)) {
+	e.hooks.mu.Lock()
+	defer e.hooks.mu.Unlock()
+	e.hooks.onDelete = fn
+}
+
+// OnSkip registers fn to be called for each entry kept, with the reason it was kept.
+func (e *Engine) OnSkip(fn func(path, reason string)) {
+	e.hooks.mu.Lock()
+	defer e.hooks.mu.Unlock()
+	e.hooks.onSkip = fn
+}
+
+// OnError registers fn to be called for each file that could not be deleted after all retries.
+func (e *Engine) OnError(fn func(path string, err error)) {
+	e.hooks.mu.Lock()
+	defer e.hooks.mu.Unlock()
+	e.hooks.onError = fn
+}
+
+// match, delete, skip and fail invoke the registered callbacks and do nothing on nil hooks.
+
+func (h *hooks) match(path string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.onMatch != nil {
+		h.onMatch(path)
+	}
+}
+
+func (h *hooks) delete(path string, size int64) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.onDelete != nil {
+		h.onDelete(path, size)
+	}
+}
+
+func (h *hooks) skip(path, reason string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.onSkip != nil {
+		h.onSkip(path, reason)
+	}
+}
+
+func (h *hooks) fail(path string, err error) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.onError != nil {
+		h.onError(path, err)
+	}
+}
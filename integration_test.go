@@ -0,0 +1,115 @@
+package tasker_test
+
+import (
+	"io"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	tasker "github.com/nilsonmart/file_delete_tasker"
+	"github.com/nilsonmart/file_delete_tasker/internal/testenv"
+)
+
+// generate builds a tree in a temporary directory.
+func generate(t *testing.T, opts testenv.Options) *testenv.Manifest {
+	t.Helper()
+	m, err := testenv.Generate(filepath.Join(t.TempDir(), "tree"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// checkTree fails the test for every entry that differs from the expected outcome.
+func checkTree(t *testing.T, m *testenv.Manifest) {
+	t.Helper()
+	problems, err := m.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range problems {
+		t.Error(p)
+	}
+}
+
+// TestEngineGeneratedTrees cleans several generated trees with files in use at once, each through an
+// engine of its own over the tree's busy filesystem, while a shared engine cleans another tree, and
+// checks the outcome and the retry and failure counters.
+func TestEngineGeneratedTrees(t *testing.T) {
+	engine := tasker.NewEngine(
+		tasker.WithWorkers(16),
+		tasker.WithRetry(testenv.MaxBusy, time.Second),
+		tasker.WithLogger(io.Discard),
+	)
+
+	var wg sync.WaitGroup
+	for seed := int64(1); seed <= 4; seed++ {
+		opts := testenv.DefaultOptions()
+		opts.Seed, opts.Files, opts.MaxSize, opts.Busy = seed, 500, 4<<10, 0.1
+		m := generate(t, opts)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			treeEngine := tasker.NewEngine(
+				tasker.WithWorkers(16),
+				tasker.WithRetry(testenv.MaxBusy, time.Second),
+				tasker.WithLogger(io.Discard),
+				tasker.WithFS(m.FS()),
+			)
+			stats, err := treeEngine.Clean(m.Dir)
+
+			var deleted, failed, inUse int
+			for _, e := range m.Entries {
+				matching := filepath.Ext(e.Name) == m.Extension && e.Kind != testenv.KindDir
+				switch {
+				case matching && e.Busy < 0:
+					// Every retry fails as in use; the last attempt counts as a failure instead.
+					failed++
+					inUse += testenv.MaxBusy
+				case matching:
+					deleted++
+					inUse += e.Busy
+				}
+			}
+
+			if (err != nil) != (failed > 0) {
+				t.Errorf("seed %d: error %v with %d files permanently in use", m.Seed, err, failed)
+			}
+			if stats.Deleted != deleted || stats.Failed != failed || stats.Retries["in use"] != inUse {
+				t.Errorf("seed %d: deleted %d, failed %d, in-use retries %d; want %d, %d, %d",
+					m.Seed, stats.Deleted, stats.Failed, stats.Retries["in use"], deleted, failed, inUse)
+			}
+			checkTree(t, m)
+		}()
+	}
+
+	// The shared engine cleans a tree of its own alongside the others.
+	opts := testenv.DefaultOptions()
+	opts.Seed, opts.Files, opts.MaxSize = 99, 500, 4<<10
+	m := generate(t, opts)
+	if _, err := engine.Clean(m.Dir); err != nil {
+		t.Error(err)
+	}
+	wg.Wait()
+	checkTree(t, m)
+}
+
+// TestCommandLineGeneratedTree runs the command line over a generated tree holding a stale lock.
+func TestCommandLineGeneratedTree(t *testing.T) {
+	t.Setenv("TASKER_STATE_DIR", t.TempDir())
+
+	for _, args := range [][]string{nil, {"-lock"}} {
+		opts := testenv.DefaultOptions()
+		opts.Seed, opts.Files, opts.MaxSize, opts.Lock = 7, 300, 4<<10, true
+		m := generate(t, opts)
+
+		app := &tasker.Application{
+			Validator: &tasker.DirectoryValidator{},
+			Deleter:   &tasker.FileDeleter{Extension: m.Extension, Log: io.Discard},
+		}
+		app.Run(append(args, m.Dir))
+		checkTree(t, m)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package testenv
+
+import "syscall"
+
+// errInUse is what removing a file held open elsewhere fails with.
+const errInUse = syscall.EBUSY
@@ -0,0 +1,8 @@
+//go:build windows
+
+package testenv
+
+import "syscall"
+
+// errInUse is ERROR_SHARING_VIOLATION, what removing a file held open elsewhere fails with.
+const errInUse = syscall.Errno(32)
@@ -0,0 +1,303 @@
+// Package testenv generates randomized directory trees for exercising the tasker end to end and
+// checks a tree against the expected outcome after a run. It backs the taskertest command and
+// the integration tests.
+package testenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	tasker "github.com/nilsonmart/file_delete_tasker"
+)
+
+// manifestSuffix names the file beside the generated directory describing what was generated.
+const manifestSuffix = ".taskertest.json"
+
+// staleLockAge is how old a generated lock file is, well past the default -lock-stale.
+const staleLockAge = 30 * 24 * time.Hour
+
+// MaxBusy is the most failed attempts generated for a file that is only briefly in use. Such files
+// are expected to be deleted, which needs at least this many retries; the default is 3.
+const MaxBusy = 3
+
+// Entry kinds.
+const (
+	KindFile    = "file"
+	KindSymlink = "symlink"
+	KindDir     = "dir"
+	KindLock    = "lock"
+)
+
+// Entry is one generated directory entry and whether a run should delete it.
+type Entry struct {
+	Name    string    `json:"name"`
+	Kind    string    `json:"kind"`
+	Size    int64     `json:"size,omitempty"`
+	ModTime time.Time `json:"mod_time"`
+	Target  string    `json:"target,omitempty"`
+
+	// Busy is how many removals fail as if another program held the file open, or -1 for all of
+	// them. It only takes effect through the manifest's FS.
+	Busy int `json:"busy,omitempty"`
+
+	Delete bool `json:"delete"`
+}
+
+// Manifest records a generated tree.
+type Manifest struct {
+	Dir       string  `json:"dir"`
+	Seed      int64   `json:"seed"`
+	Extension string  `json:"extension"`
+	Entries   []Entry `json:"entries"`
+}
+
+// Options control the shape of a generated tree.
+type Options struct {
+	Files     int
+	Seed      int64
+	Extension string
+	Match     float64       // share of files carrying Extension
+	MaxSize   int64         // largest file size in bytes
+	MaxAge    time.Duration // oldest modification time
+	Symlinks  float64       // share of entries that are symbolic links
+	Dirs      float64       // share of entries that are subdirectories
+	Busy      float64       // share of files in use, of which a quarter stay in use for good
+	Lock      bool          // leave a stale .tasker-lock behind
+}
+
+// DefaultOptions returns the options the taskertest command starts from.
+func DefaultOptions() Options {
+	return Options{
+		Files:     1000,
+		Seed:      time.Now().UnixNano(),
+		Extension: tasker.DefaultExtension,
+		Match:     0.5,
+		MaxSize:   64 << 10,
+		MaxAge:    90 * 24 * time.Hour,
+		Symlinks:  0.05,
+		Dirs:      0.02,
+	}
+}
+
+// validate rejects options no tree can be generated from.
+func (o Options) validate() error {
+	switch {
+	case o.Files < 0:
+		return fmt.Errorf("invalid options: Files must not be negative, got %d", o.Files)
+	case o.MaxSize < 0:
+		return fmt.Errorf("invalid options: MaxSize must not be negative, got %d", o.MaxSize)
+	case o.MaxAge < 0:
+		return fmt.Errorf("invalid options: MaxAge must not be negative, got %v", o.MaxAge)
+	}
+	for _, share := range []struct {
+		name  string
+		value float64
+	}{{"Match", o.Match}, {"Symlinks", o.Symlinks}, {"Dirs", o.Dirs}, {"Busy", o.Busy}} {
+		// The negated comparison also rejects NaN.
+		if !(share.value >= 0 && share.value <= 1) {
+			return fmt.Errorf("invalid options: %s must be a share between 0 and 1, got %v", share.name, share.value)
+		}
+	}
+	if o.Symlinks+o.Dirs > 1 {
+		return fmt.Errorf("invalid options: Symlinks and Dirs add up to more than 1")
+	}
+	return nil
+}
+
+// Generate creates a randomized tree in dirPath, which must not exist yet, and writes its manifest
+// next to it. Symbolic links point into a sibling directory, dirPath.targets, which a run over
+// dirPath must leave alone.
+func Generate(dirPath string, opts Options) (*Manifest, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	// Links are created with absolute targets so they resolve from inside the tree.
+	dirPath, err := filepath.Abs(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Mkdir(dirPath, 0o755); err != nil {
+		return nil, err
+	}
+	targets := dirPath + ".targets"
+	if err := os.MkdirAll(targets, 0o755); err != nil {
+		return nil, err
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	now := time.Now()
+	manifest := &Manifest{Dir: dirPath, Seed: opts.Seed, Extension: opts.Extension}
+
+	for i := 0; i < opts.Files; i++ {
+		ext := ".txt"
+		if rng.Float64() < opts.Match {
+			ext = opts.Extension
+		}
+		name := fmt.Sprintf("f%06d%s", i, ext)
+		e := Entry{
+			Name:    name,
+			Kind:    KindFile,
+			ModTime: now.Add(-time.Duration(rng.Int63n(int64(opts.MaxAge) + 1))).Truncate(time.Second),
+			Delete:  ext == opts.Extension,
+		}
+		path := filepath.Join(dirPath, name)
+
+		switch r := rng.Float64(); {
+		case r < opts.Dirs:
+			// Directories are never deleted, whatever their name, and their contents are not visited.
+			e.Kind, e.Delete = KindDir, false
+			if err := os.Mkdir(path, 0o755); err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(filepath.Join(path, "inner"+opts.Extension), nil, 0o644); err != nil {
+				return nil, err
+			}
+		case r < opts.Dirs+opts.Symlinks:
+			// Deleting a matching link must leave its target alone.
+			e.Kind = KindSymlink
+			e.Target = filepath.Join(targets, name)
+			if err := os.WriteFile(e.Target, []byte(name), 0o644); err != nil {
+				return nil, err
+			}
+			if err := os.Symlink(e.Target, path); err != nil {
+				return nil, err
+			}
+		default:
+			e.Size = rng.Int63n(opts.MaxSize + 1)
+			if err := writeRandom(path, e.Size, rng); err != nil {
+				return nil, err
+			}
+			if rng.Float64() < opts.Busy {
+				if rng.Intn(4) == 0 {
+					e.Busy = -1
+					e.Delete = false
+				} else {
+					e.Busy = 1 + rng.Intn(MaxBusy)
+				}
+			}
+		}
+		if e.Kind != KindSymlink {
+			if err := os.Chtimes(path, e.ModTime, e.ModTime); err != nil {
+				return nil, err
+			}
+		}
+		manifest.Entries = append(manifest.Entries, e)
+	}
+
+	if opts.Lock {
+		lock := Entry{Name: ".tasker-lock", Kind: KindLock, ModTime: now.Add(-staleLockAge).Truncate(time.Second)}
+		path := filepath.Join(dirPath, lock.Name)
+		if err := os.WriteFile(path, []byte("taskertest pid 0\n"), 0o644); err != nil {
+			return nil, err
+		}
+		if err := os.Chtimes(path, lock.ModTime, lock.ModTime); err != nil {
+			return nil, err
+		}
+		manifest.Entries = append(manifest.Entries, lock)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return manifest, os.WriteFile(ManifestPath(dirPath), data, 0o644)
+}
+
+// Load reads the manifest of the tree generated in dirPath.
+func Load(dirPath string) (*Manifest, error) {
+	data, err := os.ReadFile(ManifestPath(dirPath))
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %v", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %v", err)
+	}
+	manifest.Dir = dirPath
+	return &manifest, nil
+}
+
+// Check compares the tree with the expected outcome of a run with only -ext set and describes
+// every entry that differs.
+func (m *Manifest) Check() ([]string, error) {
+	var problems []string
+	for _, e := range m.Entries {
+		path := filepath.Join(m.Dir, e.Name)
+		_, err := os.Lstat(path)
+		exists := err == nil
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		switch {
+		case e.Kind == KindLock:
+			// A stale lock stays without -lock, and is taken over and released with it.
+		case e.Delete && exists:
+			problems = append(problems, fmt.Sprintf("Not deleted: %s (%s)", path, e.Kind))
+		case !e.Delete && !exists:
+			problems = append(problems, fmt.Sprintf("Wrongly deleted: %s (%s)", path, e.Kind))
+		}
+		if e.Target != "" {
+			if _, err := os.Stat(e.Target); err != nil {
+				problems = append(problems, fmt.Sprintf("Link target lost: %s: %v", e.Target, err))
+			}
+		}
+		if e.Kind == KindDir {
+			if _, err := os.Stat(filepath.Join(path, "inner"+m.Extension)); err != nil {
+				problems = append(problems, fmt.Sprintf("Subdirectory contents lost: %s: %v", path, err))
+			}
+		}
+	}
+	return problems, nil
+}
+
+// FS returns the local filesystem with the manifest's busy files failing removal as in use, for
+// tests driving a tasker.Engine through tasker.WithFS.
+func (m *Manifest) FS() tasker.FS {
+	fsys := &busyFS{remaining: make(map[string]int)}
+	for _, e := range m.Entries {
+		if e.Busy != 0 {
+			fsys.remaining[filepath.Join(m.Dir, e.Name)] = e.Busy
+		}
+	}
+	return fsys
+}
+
+// busyFS fails removals of files still counted as busy.
+type busyFS struct {
+	mu        sync.Mutex
+	remaining map[string]int
+}
+
+func (b *busyFS) ReadDir(name string) ([]os.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+func (b *busyFS) Remove(name string) error {
+	b.mu.Lock()
+	n := b.remaining[name]
+	if n > 0 {
+		b.remaining[name]--
+	}
+	b.mu.Unlock()
+	if n != 0 {
+		return &os.PathError{Op: "remove", Path: name, Err: errInUse}
+	}
+	return os.Remove(name)
+}
+
+// ManifestPath keeps the manifest outside the generated directory so it is never a candidate.
+func ManifestPath(dirPath string) string {
+	return filepath.Clean(dirPath) + manifestSuffix
+}
+
+// writeRandom writes size pseudo-random bytes to path.
+func writeRandom(path string, size int64, rng *rand.Rand) error {
+	buf := make([]byte, size)
+	rng.Read(buf)
+	return os.WriteFile(path, buf, 0o644)
+}
@@ -0,0 +1,42 @@
+package testenv
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateRejectsInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Options)
+	}{
+		{"negative files", func(o *Options) { o.Files = -1 }},
+		{"negative size", func(o *Options) { o.MaxSize = -1 }},
+		{"negative age", func(o *Options) { o.MaxAge = -1 }},
+		{"match above one", func(o *Options) { o.Match = 1.5 }},
+		{"negative symlinks", func(o *Options) { o.Symlinks = -0.1 }},
+		{"dirs NaN", func(o *Options) { o.Dirs = math.NaN() }},
+		{"busy above one", func(o *Options) { o.Busy = 2 }},
+		{"links and dirs above one", func(o *Options) { o.Symlinks, o.Dirs = 0.6, 0.6 }},
+	}
+	for _, tt := range tests {
+		opts := DefaultOptions()
+		opts.Files = 10
+		tt.modify(&opts)
+		dir := filepath.Join(t.TempDir(), "tree")
+		if _, err := Generate(dir, opts); err == nil {
+			t.Errorf("%s: Generate succeeded", tt.name)
+		}
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("%s: directory created for invalid options", tt.name)
+		}
+	}
+
+	opts := DefaultOptions()
+	opts.Files, opts.MaxSize, opts.MaxAge = 10, 0, 0
+	if _, err := Generate(filepath.Join(t.TempDir(), "tree"), opts); err != nil {
+		t.Errorf("zero size and age: %v", err)
+	}
+}
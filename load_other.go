@@ -0,0 +1,10 @@
+//go:build !linux
+
+package tasker
+
+import "errors"
+
+// systemLoad is unavailable outside Linux.
+func systemLoad() (float64, error) {
+	return 0, errors.New("load-based throttling is only available on Linux")
+}
@@ -0,0 +1,124 @@
+package tasker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lockFileName is the lock file taken in a directory so only one node cleans it at a time.
+const lockFileName = ".tasker-lock"
+
+// DirectoryLock is an exclusive lock on a directory shared between nodes, held as a lock file
+// created with O_EXCL inside it. Exclusive creation is atomic on local disks, SMB and NFSv4 but
+// not on NFSv2/3, where two nodes may rarely both succeed. While held, the lock file's timestamp
+// is refreshed so that long runs are not mistaken for crashed ones.
+type DirectoryLock struct {
+	path string
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// AcquireDirectoryLock takes the lock on dirPath. A lock file older than stale is assumed to be
+// left behind by a crashed node and is taken over; a zero stale never takes over.
+func AcquireDirectoryLock(dirPath string, stale time.Duration) (*DirectoryLock, error) {
+	path := filepath.Join(dirPath, lockFileName)
+	hostname, _ := os.Hostname()
+	owner := fmt.Sprintf("%s pid %d since %s\n", hostname, os.Getpid(), time.Now().UTC().Format(time.RFC3339))
+
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			_, werr := f.WriteString(owner)
+			if cerr := f.Close(); werr == nil {
+				werr = cerr
+			}
+			if werr != nil {
+				os.Remove(path)
+				return nil, werr
+			}
+			lock := &DirectoryLock{path: path, stop: make(chan struct{})}
+			lock.heartbeat(stale)
+			return lock, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		info, serr := os.Stat(path)
+		if serr != nil {
+			// Released between our attempt and the stat; try again.
+			continue
+		}
+		holder, _ := os.ReadFile(path)
+		if stale == 0 || time.Since(info.ModTime()) < stale {
+			return nil, fmt.Errorf("%s is locked by %s", dirPath, strings.TrimSpace(string(holder)))
+		}
+		fmt.Printf("Taking over stale lock on %s held by %s\n", dirPath, strings.TrimSpace(string(holder)))
+		if err := takeOverStaleLock(path, stale); err != nil {
+			return nil, fmt.Errorf("%s is locked: %v", dirPath, err)
+		}
+	}
+	return nil, fmt.Errorf("%s is locked by another node", dirPath)
+}
+
+// takeOverStaleLock moves a stale lock file aside. Renaming is atomic, so when several nodes find
+// the same stale lock only one of them moves it; the others see it gone and compete for the new
+// lock through O_EXCL. If the file moved aside turns out to be fresh, because another node took
+// the lock over in the meantime, it is put back.
+func takeOverStaleLock(path string, stale time.Duration) error {
+	aside := fmt.Sprintf("%s.stale-%d-%d", path, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(path, aside); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	info, err := os.Stat(aside)
+	if err == nil && time.Since(info.ModTime()) < stale {
+		// Link does not replace an existing file, so a lock taken since is never overwritten.
+		if err := os.Link(aside, path); err != nil && !os.IsExist(err) {
+			os.Rename(aside, path)
+		} else {
+			os.Remove(aside)
+		}
+		return errors.New("lock was taken over by another node")
+	}
+	return os.Remove(aside)
+}
+
+// heartbeat refreshes the lock file's timestamp well within stale until the lock is released.
+func (l *DirectoryLock) heartbeat(stale time.Duration) {
+	if stale == 0 {
+		return
+	}
+	interval := max(stale/3, time.Second)
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-l.stop:
+				return
+			case <-ticker.C:
+				now := time.Now()
+				if err := os.Chtimes(l.path, now, now); err != nil {
+					fmt.Println("Error refreshing directory lock:", err)
+				}
+			}
+		}
+	}()
+}
+
+// Release stops refreshing the lock and removes the lock file.
+func (l *DirectoryLock) Release() error {
+	close(l.stop)
+	l.wg.Wait()
+	return os.Remove(l.path)
+}
@@ -0,0 +1,71 @@
+package tasker
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDirectoryLockStaleTakeoverHasOneWinner(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, lockFileName)
+	if err := os.WriteFile(path, []byte("crashed pid 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	const nodes = 8
+	locks := make(chan *DirectoryLock, nodes)
+	var wg sync.WaitGroup
+	for i := 0; i < nodes; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if lock, err := AcquireDirectoryLock(dir, time.Minute); err == nil {
+				locks <- lock
+			}
+		}()
+	}
+	wg.Wait()
+	close(locks)
+
+	if len(locks) != 1 {
+		t.Fatalf("%d nodes took the lock, want 1", len(locks))
+	}
+	if err := (<-locks).Release(); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("%d files left behind after release", len(entries))
+	}
+}
+
+func TestDirectoryLockHeartbeat(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := AcquireDirectoryLock(dir, 3*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lock.Release()
+
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lock.path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(1500 * time.Millisecond)
+	info, err := os.Stat(lock.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(info.ModTime()) > time.Minute {
+		t.Errorf("lock file not refreshed, modified %v", info.ModTime())
+	}
+	if _, err := AcquireDirectoryLock(dir, 3*time.Second); err == nil {
+		t.Error("second node took over a lock that is still held")
+	}
+}
@@ -0,0 +1,130 @@
+package tasker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// lowMemoryBatch is how many directory entries are read at a time in low-memory mode.
+	lowMemoryBatch = 64
+
+	// lowMemoryMaxErrors caps how many error messages are kept; further failures are only counted.
+	lowMemoryMaxErrors = 20
+
+	// retryBackoff is the pause before the first retry of a removal; it grows with every attempt.
+	retryBackoff = 100 * time.Millisecond
+)
+
+// DeleteFilesLowMemory streams the directory in small batches and deletes matching files one at a
+// time with a single worker, so memory use stays flat regardless of how many files the directory holds.
+// Like DeleteFilesWithTimeout it gives each attempt timeout, waits for the load throttle and counts retries.
+func (fd *FileDeleter) DeleteFilesLowMemory(dirPath string, maxRetries int, timeout time.Duration) error {
+	dir, err := os.Open(dirPath)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	var errors []string
+	failed := 0
+	for {
+		batch, err := dir.ReadDir(lowMemoryBatch)
+		for _, file := range batch {
+			if !fd.matches(dirPath, file) {
+				continue
+			}
+
+			filePath := filepath.Join(dirPath, file.Name())
+			var size int64
+			if info, err := file.Info(); err == nil {
+				size = info.Size()
+			}
+
+			err := removeWithRetries(filePath, maxRetries, timeout, fd.Throttle, fd.Stats)
+			if os.IsNotExist(err) {
+				fd.Stats.AddGone(filePath)
+				fmt.Printf("Already gone: %s\n", filePath)
+				continue
+			}
+			if err != nil {
+				fd.Stats.AddFailed(filePath)
+				fd.FollowUps.run(filePath, err)
+				failed++
+				if len(errors) < lowMemoryMaxErrors {
+					errors = append(errors, fmt.Sprintf("failed to delete file after %d retries: %s, %v", maxRetries, filePath, err))
+				}
+				continue
+			}
+			fd.Stats.AddDeleted(filePath, size)
+			fmt.Printf("Deleted file: %s\n", filePath)
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if failed > len(errors) {
+		errors = append(errors, fmt.Sprintf("and %d more", failed-len(errors)))
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("errors occurred during file deletion: %s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+// removeWithRetries removes a file, retrying up to maxRetries times with a growing pause in between.
+// Each attempt waits for throttle and is abandoned after timeout; retries are counted in stats. A nil
+// throttle or stats is ignored.
+func removeWithRetries(path string, maxRetries int, timeout time.Duration, throttle *LoadThrottle, stats *RunStats) error {
+	var err error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			class := errorClass(err)
+			stats.AddRetry(class)
+			fmt.Printf("Retrying file: %s (%s, attempt %d of %d)\n", path, class, attempt+1, maxRetries+1)
+			time.Sleep(time.Duration(attempt) * retryBackoff)
+		}
+		throttle.Wait()
+		err = removeWithTimeout(path, timeout)
+		if errors.Is(err, os.ErrNotExist) {
+			// After a failed attempt, a missing file means that attempt removed it after all.
+			if attempt > 0 {
+				return nil
+			}
+			return err
+		}
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// removeWithTimeout removes a file, giving up with context.DeadlineExceeded after timeout. A removal
+// still hanging on a stalled share completes or fails in the background.
+func removeWithTimeout(path string, timeout time.Duration) error {
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- os.Remove(path)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-errChan:
+		return err
+	case <-timer.C:
+		return context.DeadlineExceeded
+	}
+}
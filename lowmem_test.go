@@ -0,0 +1,28 @@
+package tasker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRemoveWithRetriesCountsRetries(t *testing.T) {
+	// A directory with contents cannot be removed, so every attempt fails.
+	path := filepath.Join(t.TempDir(), "busy")
+	if err := os.MkdirAll(filepath.Join(path, "inner"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := &RunStats{}
+	if err := removeWithRetries(path, 2, time.Second, nil, stats); err == nil {
+		t.Fatal("removing a non-empty directory succeeded")
+	}
+	retries := 0
+	for _, n := range stats.Record().Retries {
+		retries += n
+	}
+	if retries != 2 {
+		t.Errorf("counted %d retries, want 2", retries)
+	}
+}
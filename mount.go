@@ -0,0 +1,12 @@
+package tasker
+
+import "path/filepath"
+
+// resolveDir returns the absolute, symlink-free form of a directory path.
+func resolveDir(dirPath string) (string, error) {
+	abs, err := filepath.Abs(dirPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
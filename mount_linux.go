@@ -0,0 +1,53 @@
+//go:build linux
+
+package tasker
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// isMountPoint reports whether the directory is listed as a mount point in /proc/self/mountinfo,
+// which also catches bind mounts that share a device with their parent.
+func isMountPoint(dirPath string) (bool, error) {
+	resolved, err := resolveDir(dirPath)
+	if err != nil {
+		return false, err
+	}
+
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 4 && unescapeMountPath(fields[4]) == resolved {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// unescapeMountPath decodes the octal escapes (e.g. \040 for a space) used in mountinfo paths.
+func unescapeMountPath(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
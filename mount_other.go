@@ -0,0 +1,10 @@
+//go:build !unix && !windows
+
+package tasker
+
+import "errors"
+
+// isMountPoint is unavailable on this platform.
+func isMountPoint(dirPath string) (bool, error) {
+	return false, errors.New("mount point detection is not supported on this platform")
+}
@@ -0,0 +1,31 @@
+//go:build unix && !linux
+
+package tasker
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// isMountPoint reports whether the directory sits on a different device than its parent.
+func isMountPoint(dirPath string) (bool, error) {
+	resolved, err := resolveDir(dirPath)
+	if err != nil {
+		return false, err
+	}
+	parent := filepath.Dir(resolved)
+	if parent == resolved {
+		return true, nil
+	}
+
+	dirInfo, err := os.Stat(resolved)
+	if err != nil {
+		return false, err
+	}
+	parentInfo, err := os.Stat(parent)
+	if err != nil {
+		return false, err
+	}
+	return dirInfo.Sys().(*syscall.Stat_t).Dev != parentInfo.Sys().(*syscall.Stat_t).Dev, nil
+}
@@ -0,0 +1,32 @@
+//go:build windows
+
+package tasker
+
+import (
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var procGetVolumePathNameW = syscall.NewLazyDLL("kernel32.dll").NewProc("GetVolumePathNameW")
+
+// isMountPoint reports whether the directory is the root of a volume, either a drive root or a
+// volume mounted into a folder.
+func isMountPoint(dirPath string) (bool, error) {
+	resolved, err := resolveDir(dirPath)
+	if err != nil {
+		return false, err
+	}
+	p, err := syscall.UTF16PtrFromString(resolved)
+	if err != nil {
+		return false, err
+	}
+
+	buf := make([]uint16, syscall.MAX_PATH+1)
+	r, _, err := procGetVolumePathNameW.Call(uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	if r == 0 {
+		return false, err
+	}
+	volume := strings.TrimSuffix(syscall.UTF16ToString(buf), `\`)
+	return strings.EqualFold(volume, strings.TrimSuffix(resolved, `\`)), nil
+}
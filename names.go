@@ -0,0 +1,81 @@
+package tasker
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NameForm is the Unicode normalization file names and extensions are brought to before they are
+// compared. macOS and many SMB servers store names decomposed (NFD) while most other systems
+// write them composed (NFC), so the same visible name can differ byte for byte. Names are only
+// normalized for comparison; files are always opened and reported under their on-disk name.
+type NameForm int
+
+const (
+	NameFormNFC  NameForm = iota // compare composed forms (the default)
+	NameFormNFD                  // compare decomposed forms
+	NameFormNone                 // compare names byte for byte
+)
+
+// parseNameForm parses the value of -normalize.
+func parseNameForm(s string) (NameForm, error) {
+	switch strings.ToLower(s) {
+	case "nfc":
+		return NameFormNFC, nil
+	case "nfd":
+		return NameFormNFD, nil
+	case "none":
+		return NameFormNone, nil
+	}
+	return 0, fmt.Errorf("-normalize must be nfc, nfd or none, got %q", s)
+}
+
+// normalize returns name in the form used for comparisons.
+func (f NameForm) normalize(name string) string {
+	switch f {
+	case NameFormNFC:
+		return norm.NFC.String(name)
+	case NameFormNFD:
+		return norm.NFD.String(name)
+	}
+	return name
+}
+
+// windowsDeviceNames are reserved by Win32 in every directory, with or without an extension.
+var windowsDeviceNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// win32NameProblem explains why a name cannot be used safely through ordinary Win32 paths, or
+// returns an empty string. Device names such as NUL.rdp open the device rather than the file,
+// and Win32 strips trailing dots and spaces, so "report.rdp." would act on "report.rdp". Such
+// files can only be reached through \\?\ paths, which every filter would need, so they are kept.
+func win32NameProblem(name string) string {
+	if strings.TrimRight(name, ". ") != name {
+		return "name ends in a dot or space"
+	}
+	stem, _, _ := strings.Cut(name, ".")
+	if windowsDeviceNames[strings.ToUpper(strings.TrimRight(stem, " "))] {
+		return "reserved Windows device name"
+	}
+	return ""
+}
+
+// variants returns name followed by its other normalized spellings, for looking a name up on a
+// filesystem that does not normalize itself.
+func (f NameForm) variants(name string) []string {
+	if f == NameFormNone {
+		return []string{name}
+	}
+	names := []string{name}
+	for _, v := range []string{norm.NFC.String(name), norm.NFD.String(name)} {
+		if v != names[len(names)-1] && v != name {
+			names = append(names, v)
+		}
+	}
+	return names
+}
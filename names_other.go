@@ -0,0 +1,11 @@
+//go:build !windows
+
+package tasker
+
+// windowsNames reports whether names are subject to Win32 path rules.
+const windowsNames = false
+
+// longPathName has no short names to expand outside Windows.
+func longPathName(path string) string {
+	return path
+}
@@ -0,0 +1,32 @@
+package tasker
+
+import "testing"
+
+func TestWin32NameProblem(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"report.rdp", ""},
+		{"report.rdp.", "name ends in a dot or space"},
+		{"report.rdp ", "name ends in a dot or space"},
+		{"report.rdp. .", "name ends in a dot or space"},
+		{"report. rdp", ""},
+		{".rdp", ""},
+		{"NUL.rdp", "reserved Windows device name"},
+		{"nul.rdp", "reserved Windows device name"},
+		{"Con", "reserved Windows device name"},
+		{"COM1.tar.gz", "reserved Windows device name"},
+		{"LPT9.rdp", "reserved Windows device name"},
+		{"aux .rdp", "reserved Windows device name"},
+		{"COM0.rdp", ""},
+		{"COM10.rdp", ""},
+		{"CONSOLE.rdp", ""},
+		{"my-nul.rdp", ""},
+	}
+	for _, tt := range tests {
+		if got := win32NameProblem(tt.name); got != tt.want {
+			t.Errorf("win32NameProblem(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package tasker
+
+import "syscall"
+
+// windowsNames reports whether names are subject to Win32 path rules.
+const windowsNames = true
+
+// longPathName expands 8.3 short components such as PROGRA~1 so a directory is recorded and
+// compared under one name. The path is returned unchanged if it cannot be resolved.
+func longPathName(path string) string {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return path
+	}
+	buf := make([]uint16, syscall.MAX_PATH)
+	for {
+		n, err := syscall.GetLongPathName(p, &buf[0], uint32(len(buf)))
+		if err != nil || n == 0 {
+			return path
+		}
+		if int(n) <= len(buf) {
+			return syscall.UTF16ToString(buf[:n])
+		}
+		buf = make([]uint16, n)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package tasker
+
+import (
+	"errors"
+	"os"
+)
+
+// fileOwner is unavailable on platforms without POSIX ownership.
+func fileOwner(info os.FileInfo) (string, error) {
+	return "", errors.New("file ownership is only available on Unix platforms")
+}
@@ -0,0 +1,67 @@
+package tasker
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// PathVars holds the values available to templated directory paths, e.g. /data/{{.Year}}/{{.Month}}.
+type PathVars struct {
+	Year     string
+	Month    string
+	Day      string
+	Hostname string
+}
+
+// envReference matches Windows-style %VARIABLE% references.
+var envReference = regexp.MustCompile(`%([A-Za-z_][A-Za-z0-9_]*)%`)
+
+// ExpandPath expands template fields and %VARIABLE% environment references in a path at run time.
+// %HOSTNAME% falls back to the machine's host name when the variable is not set.
+func ExpandPath(path string, now time.Time) (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+
+	var expandErr error
+	path = envReference.ReplaceAllStringFunc(path, func(ref string) string {
+		name := strings.Trim(ref, "%")
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+		if strings.EqualFold(name, "HOSTNAME") {
+			return hostname
+		}
+		expandErr = fmt.Errorf("environment variable %s is not set", name)
+		return ref
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+
+	if !strings.Contains(path, "{{") {
+		return path, nil
+	}
+
+	tmpl, err := template.New("path").Option("missingkey=error").Parse(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid path template: %v", err)
+	}
+	vars := PathVars{
+		Year:     now.Format("2006"),
+		Month:    now.Format("01"),
+		Day:      now.Format("02"),
+		Hostname: hostname,
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("invalid path template: %v", err)
+	}
+	return b.String(), nil
+}
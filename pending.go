@@ -0,0 +1,113 @@
+package tasker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// pendingMarkerName is the marker file announcing upcoming deletions to users of a directory.
+const pendingMarkerName = ".tasker-pending"
+
+// PendingDeletions implements the warn-then-delete policy: a matching file is first announced in a
+// marker file next to it and only becomes eligible for deletion once its grace period has expired.
+type PendingDeletions struct {
+	dirPath   string
+	grace     time.Duration
+	now       time.Time
+	deadlines map[string]time.Time
+	seen      map[string]bool
+}
+
+// LoadPendingDeletions reads the marker file in dirPath, if one exists.
+func LoadPendingDeletions(dirPath string, grace time.Duration) (*PendingDeletions, error) {
+	pd := &PendingDeletions{
+		dirPath:   dirPath,
+		grace:     grace,
+		now:       time.Now(),
+		deadlines: make(map[string]time.Time),
+		seen:      make(map[string]bool),
+	}
+
+	f, err := os.Open(filepath.Join(dirPath, pendingMarkerName))
+	if os.IsNotExist(err) {
+		return pd, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		deadline, name, ok := strings.Cut(line, "\t")
+		if !ok {
+			return nil, fmt.Errorf("malformed line in %s: %q", pendingMarkerName, line)
+		}
+		t, err := time.Parse(time.RFC3339, deadline)
+		if err != nil {
+			return nil, fmt.Errorf("malformed deadline in %s: %v", pendingMarkerName, err)
+		}
+		pd.deadlines[name] = t
+	}
+	return pd, scanner.Err()
+}
+
+// Filter accepts files whose announced grace period has expired and announces the others.
+func (pd *PendingDeletions) Filter(path string) (bool, error) {
+	name := filepath.Base(path)
+	if name == pendingMarkerName {
+		return false, nil
+	}
+	pd.seen[name] = true
+
+	deadline, ok := pd.deadlines[name]
+	if !ok {
+		deadline = pd.now.Add(pd.grace)
+		pd.deadlines[name] = deadline
+		fmt.Printf("Announced deletion: %s (not before %s)\n", path, deadline.Format(time.RFC3339))
+		return false, nil
+	}
+	return !pd.now.Before(deadline), nil
+}
+
+// Save rewrites the marker file with the files still awaiting deletion, dropping entries that were
+// deleted or no longer match. The marker is removed once nothing is pending.
+func (pd *PendingDeletions) Save() error {
+	markerPath := filepath.Join(pd.dirPath, pendingMarkerName)
+
+	var names []string
+	for name := range pd.deadlines {
+		if !pd.seen[name] {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(pd.dirPath, name)); err != nil {
+			continue
+		}
+		names = append(names, name)
+	}
+
+	if len(names) == 0 {
+		if err := os.Remove(markerPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("# The files below are scheduled for deletion by tasker.\n")
+	b.WriteString("# Each line holds the earliest deletion time and the file name.\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s\t%s\n", pd.deadlines[name].Format(time.RFC3339), name)
+	}
+	return writeFileAtomic(markerPath, []byte(b.String()), 0o644)
+}
@@ -0,0 +1,169 @@
+package tasker
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Plan is the outcome of a dry run: every file a real run would have deleted. Roots planned in
+// parallel may add to the same plan.
+type Plan struct {
+	Created time.Time `json:"created"`
+	Roots   []string  `json:"roots"`
+	Files   []string  `json:"files"`
+
+	mu sync.Mutex
+}
+
+// add records a planned root and the files a real run would delete from it.
+func (p *Plan) add(root string, files []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Roots = append(p.Roots, root)
+	p.Files = append(p.Files, files...)
+}
+
+// SavePlan writes the plan as JSON.
+func SavePlan(path string, plan *Plan) error {
+	sort.Strings(plan.Files)
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0o644)
+}
+
+// LoadPlan reads a plan written by SavePlan.
+func LoadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("invalid plan %s: %v", path, err)
+	}
+	return &plan, nil
+}
+
+// DiffPlan prints which files were added to or removed from the deletion set between two plans.
+func (app *Application) DiffPlan(args []string) {
+	if len(args) != 2 {
+		fmt.Println("Usage: <program> diff-plan <old_plan.json> <new_plan.json>")
+		return
+	}
+
+	before, err := LoadPlan(args[0])
+	if err != nil {
+		fmt.Println("Error reading plan:", err)
+		return
+	}
+	after, err := LoadPlan(args[1])
+	if err != nil {
+		fmt.Println("Error reading plan:", err)
+		return
+	}
+
+	inBefore := make(map[string]bool, len(before.Files))
+	for _, f := range before.Files {
+		inBefore[f] = true
+	}
+	inAfter := make(map[string]bool, len(after.Files))
+	for _, f := range after.Files {
+		inAfter[f] = true
+	}
+
+	var added, removed int
+	for _, f := range before.Files {
+		if !inAfter[f] {
+			fmt.Println("-", f)
+			removed++
+		}
+	}
+	for _, f := range after.Files {
+		if !inBefore[f] {
+			fmt.Println("+", f)
+			added++
+		}
+	}
+	fmt.Printf("%d files added to and %d removed from the deletion set (%d before, %d after).\n",
+		added, removed, len(before.Files), len(after.Files))
+}
+
+// Replay applies a saved plan to the current filesystem: every listed file that still exists is
+// deleted, or with -verify only reported, so an approved dry run can be executed later or elsewhere.
+func (app *Application) Replay(args []string) {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	verify := flags.Bool("verify", false, "only report which planned files still exist")
+	if err := flags.Parse(args); err != nil {
+		return
+	}
+	if flags.NArg() != 1 {
+		fmt.Println("Usage: <program> replay [-verify] <plan.json>")
+		return
+	}
+
+	plan, err := LoadPlan(flags.Arg(0))
+	if err != nil {
+		fmt.Println("Error reading plan:", err)
+		return
+	}
+
+	dryRunOnly, err := LoadDryRunOnly()
+	if err != nil {
+		fmt.Println("Error reading dry-run-only paths:", err)
+		return
+	}
+
+	var present, deleted, missing, failed int
+	for _, path := range plan.Files {
+		info, err := os.Lstat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			missing++
+			continue
+		}
+		if err == nil && !info.Mode().IsRegular() {
+			err = fmt.Errorf("no longer a regular file")
+		}
+		if err != nil {
+			fmt.Printf("Skipping file: %s, %v\n", path, err)
+			failed++
+			continue
+		}
+
+		if *verify {
+			fmt.Println("Present:", path)
+			present++
+			continue
+		}
+		if dryRunOnly.Protects(path) {
+			fmt.Printf("Skipping file: %s, under a dry-run-only path\n", path)
+			failed++
+			continue
+		}
+		err = removeWithRetries(path, defaultRetries, defaultTimeout, nil, nil)
+		if errors.Is(err, os.ErrNotExist) {
+			missing++
+			continue
+		}
+		if err != nil {
+			fmt.Printf("Failed to delete file: %s, %v\n", path, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Deleted file: %s\n", path)
+		deleted++
+	}
+
+	if *verify {
+		fmt.Printf("%d of %d planned files still present, %d already gone, %d problems.\n", present, len(plan.Files), missing, failed)
+	} else {
+		fmt.Printf("Deleted %d of %d planned files, %d already gone, %d failed.\n", deleted, len(plan.Files), missing, failed)
+	}
+}
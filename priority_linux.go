@@ -0,0 +1,56 @@
+//go:build linux
+
+package tasker
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+const (
+	ioprioWhoProcess = 1
+	ioprioClassIdle  = 3
+	ioprioClassShift = 13
+)
+
+// probePriority reports whether -nice and -io-idle can be applied, without changing the priority.
+// Containers and seccomp profiles may block the priority system calls.
+func probePriority() (nice, ioIdle error) {
+	if _, err := syscall.Getpriority(syscall.PRIO_PROCESS, 0); err != nil {
+		nice = err
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_GET, ioprioWhoProcess, 0, 0); errno != 0 {
+		ioIdle = errno
+	}
+	return nice, ioIdle
+}
+
+// lowerPriority applies the CPU niceness and, optionally, the idle IO class to the whole process.
+// Linux tracks both per thread, so every thread already started by the Go runtime is updated;
+// threads created later inherit the settings.
+func lowerPriority(nice int, ioIdle bool) error {
+	tasks, err := os.ReadDir("/proc/self/task")
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+		if nice != 0 {
+			if err := syscall.Setpriority(syscall.PRIO_PROCESS, tid, nice); err != nil {
+				return err
+			}
+		}
+		if ioIdle {
+			prio := ioprioClassIdle << ioprioClassShift
+			if _, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(tid), uintptr(prio)); errno != 0 {
+				return errno
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+//go:build !unix && !windows
+
+package tasker
+
+import "errors"
+
+// probePriority reports that neither -nice nor -io-idle is available.
+func probePriority() (nice, ioIdle error) {
+	err := errors.New("process priority control is not supported on this platform")
+	return err, err
+}
+
+// lowerPriority is unavailable on this platform.
+func lowerPriority(nice int, ioIdle bool) error {
+	if nice == 0 && !ioIdle {
+		return nil
+	}
+	return errors.New("process priority control is not supported on this platform")
+}
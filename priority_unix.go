@@ -0,0 +1,27 @@
+//go:build unix && !linux
+
+package tasker
+
+import (
+	"errors"
+	"syscall"
+)
+
+// probePriority reports whether -nice and -io-idle can be applied, without changing the priority.
+func probePriority() (nice, ioIdle error) {
+	if _, err := syscall.Getpriority(syscall.PRIO_PROCESS, 0); err != nil {
+		nice = err
+	}
+	return nice, errors.New("idle IO priority is only available on Linux and Windows")
+}
+
+// lowerPriority applies the CPU niceness to the process. IO classes are a Linux feature.
+func lowerPriority(nice int, ioIdle bool) error {
+	if ioIdle {
+		return errors.New("idle IO priority is only available on Linux and Windows")
+	}
+	if nice == 0 {
+		return nil
+	}
+	return syscall.Setpriority(syscall.PRIO_PROCESS, 0, nice)
+}
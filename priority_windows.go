@@ -0,0 +1,57 @@
+//go:build windows
+
+package tasker
+
+import "syscall"
+
+const (
+	belowNormalPriorityClass   = 0x00004000
+	idlePriorityClass          = 0x00000040
+	processModeBackgroundBegin = 0x00100000
+)
+
+var (
+	procSetPriorityClass = syscall.NewLazyDLL("kernel32.dll").NewProc("SetPriorityClass")
+	procGetPriorityClass = syscall.NewLazyDLL("kernel32.dll").NewProc("GetPriorityClass")
+)
+
+// probePriority reports whether -nice and -io-idle can be applied, without changing the priority.
+// Both use the process priority class, so they stand or fall together.
+func probePriority() (nice, ioIdle error) {
+	if err := procSetPriorityClass.Find(); err != nil {
+		return err, err
+	}
+	process, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return err, err
+	}
+	if r, _, err := procGetPriorityClass.Call(uintptr(process)); r == 0 {
+		return err, err
+	}
+	return nil, nil
+}
+
+// lowerPriority maps niceness onto a Windows priority class. ioIdle switches the process to
+// background mode, which lowers both its CPU and IO priority.
+func lowerPriority(nice int, ioIdle bool) error {
+	class := uintptr(0)
+	switch {
+	case ioIdle:
+		class = processModeBackgroundBegin
+	case nice >= 15:
+		class = idlePriorityClass
+	case nice > 0:
+		class = belowNormalPriorityClass
+	default:
+		return nil
+	}
+
+	process, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return err
+	}
+	if r, _, err := procSetPriorityClass.Call(uintptr(process), class); r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package tasker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// quarantineListName is the file in the target directory listing suspicious matches awaiting confirmation.
+const quarantineListName = ".tasker-quarantine"
+
+// Quarantine holds back matching files that look suspicious, such as very recent or unusually large
+// files, listing them for manual confirmation instead of deleting them.
+type Quarantine struct {
+	dirPath    string
+	newerThan  time.Duration
+	largerThan int64
+	confirmed  map[string]bool
+	held       map[string]string
+}
+
+// NewQuarantine creates a quarantine for dirPath. A zero newerThan or largerThan disables that heuristic.
+// When confirm is set, files already on the quarantine list are released for deletion.
+func NewQuarantine(dirPath string, newerThan time.Duration, largerThan int64, confirm bool) (*Quarantine, error) {
+	q := &Quarantine{
+		dirPath:    dirPath,
+		newerThan:  newerThan,
+		largerThan: largerThan,
+		confirmed:  make(map[string]bool),
+		held:       make(map[string]string),
+	}
+	if !confirm {
+		return q, nil
+	}
+
+	f, err := os.Open(filepath.Join(dirPath, quarantineListName))
+	if os.IsNotExist(err) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, _, _ := strings.Cut(line, "\t")
+		q.confirmed[name] = true
+	}
+	return q, scanner.Err()
+}
+
+// Filter accepts unsuspicious and confirmed files and quarantines the rest.
+func (q *Quarantine) Filter(path string) (bool, error) {
+	name := filepath.Base(path)
+	if name == quarantineListName {
+		return false, nil
+	}
+	if q.confirmed[name] {
+		return true, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+
+	var reason string
+	switch {
+	case q.newerThan > 0 && time.Since(info.ModTime()) < q.newerThan:
+		reason = "modified " + info.ModTime().Format(time.RFC3339)
+	case q.largerThan > 0 && info.Size() > q.largerThan:
+		reason = ByteSize(info.Size()).String()
+	default:
+		return true, nil
+	}
+
+	q.held[name] = reason
+	fmt.Printf("Quarantined file: %s (%s)\n", path, reason)
+	return false, nil
+}
+
+// Save writes the files held back by this run to the quarantine list, removing the list when empty.
+func (q *Quarantine) Save() error {
+	listPath := filepath.Join(q.dirPath, quarantineListName)
+	if len(q.held) == 0 {
+		if err := os.Remove(listPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	names := make([]string, 0, len(q.held))
+	for name := range q.held {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("# Suspicious matches held back by tasker. Review them, remove any line for a file that must\n")
+	b.WriteString("# be kept, then run again with -confirm-quarantine to delete the files still listed.\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s\t%s\n", name, q.held[name])
+	}
+	return writeFileAtomic(listPath, []byte(b.String()), 0o644)
+}
@@ -0,0 +1,89 @@
+package tasker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// EnforceOwnerQuota deletes each owner's oldest matching files until the space that owner uses in the
+// directory is within limit bytes. Files that don't match still count towards usage but are never deleted.
+func (fd *FileDeleter) EnforceOwnerQuota(dirPath string, files []os.DirEntry, limit int64) error {
+	usage := make(map[string]int64)
+	candidates := make(map[string][]os.FileInfo)
+
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		info, err := file.Info()
+		if os.IsNotExist(err) {
+			// Removed since the listing, so it neither uses space nor needs deleting.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		owner, err := fileOwner(info)
+		if err != nil {
+			return err
+		}
+		usage[owner] += info.Size()
+		if fd.matches(dirPath, file) {
+			candidates[owner] = append(candidates[owner], info)
+		}
+	}
+
+	owners := make([]string, 0, len(usage))
+	for owner := range usage {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+
+	var errors []string
+	for _, owner := range owners {
+		fmt.Printf("Owner %s: %s used, limit %s\n", owner, ByteSize(usage[owner]), ByteSize(limit))
+		if usage[owner] <= limit {
+			continue
+		}
+
+		oldest := candidates[owner]
+		sort.Slice(oldest, func(i, j int) bool { return oldest[i].ModTime().Before(oldest[j].ModTime()) })
+		for _, info := range oldest {
+			if usage[owner] <= limit {
+				break
+			}
+			filePath := filepath.Join(dirPath, info.Name())
+			err := os.Remove(filePath)
+			if os.IsNotExist(err) {
+				// Already removed by someone else, so it no longer counts against the owner.
+				fd.Stats.AddGone(filePath)
+				usage[owner] -= info.Size()
+				fmt.Printf("  Already gone: %s\n", filePath)
+				continue
+			}
+			if err != nil {
+				fd.Stats.AddFailed(filePath)
+				fd.FollowUps.run(filePath, err)
+				errors = append(errors, fmt.Sprintf("%s, %v", filePath, err))
+				continue
+			}
+			fd.Stats.AddDeleted(filePath, info.Size())
+			usage[owner] -= info.Size()
+			fmt.Printf("  Deleted file: %s\n", filePath)
+		}
+
+		if usage[owner] > limit {
+			fmt.Printf("  Still over quota: %s used\n", ByteSize(usage[owner]))
+		} else {
+			fmt.Printf("  Now within quota: %s used\n", ByteSize(usage[owner]))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("errors occurred during quota enforcement: %s", strings.Join(errors, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package tasker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// rebootStateFile records files scheduled for deletion at the next reboot, keyed by absolute path.
+const rebootStateFile = "reboot-pending.json"
+
+// rebootFollowUp schedules files that are in use for deletion at the next reboot.
+type rebootFollowUp struct{}
+
+// Apply schedules path for deletion at reboot and records it so a later run can confirm it.
+func (rebootFollowUp) Apply(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if err := scheduleDeleteOnReboot(abs); err != nil {
+		return err
+	}
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	pending := make(map[string]time.Time)
+	if err := readState(rebootStateFile, &pending); err != nil {
+		return err
+	}
+	pending[abs] = time.Now().UTC()
+	return writeState(rebootStateFile, pending)
+}
+
+func (rebootFollowUp) String() string {
+	return "reboot"
+}
+
+// checkRebootDeletions reports files in dirPath scheduled for deletion at reboot by earlier runs.
+// Files that are gone are reported as completed and forgotten; the rest are still waiting.
+func checkRebootDeletions(dirPath string) error {
+	abs, err := filepath.Abs(dirPath)
+	if err != nil {
+		return err
+	}
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	pending := make(map[string]time.Time)
+	if err := readState(rebootStateFile, &pending); err != nil {
+		return err
+	}
+
+	var paths []string
+	for path := range pending {
+		if filepath.Dir(path) == abs {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	sort.Strings(paths)
+
+	completed := 0
+	for _, path := range paths {
+		if _, err := os.Lstat(path); os.IsNotExist(err) {
+			fmt.Printf("Completed delete-on-reboot: %s\n", path)
+			delete(pending, path)
+			completed++
+		}
+	}
+	if waiting := len(paths) - completed; waiting > 0 {
+		fmt.Printf("%d files still awaiting delete-on-reboot\n", waiting)
+	}
+	if completed == 0 {
+		return nil
+	}
+	return writeState(rebootStateFile, pending)
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package tasker
+
+import "errors"
+
+// rebootDeleteSupported reports whether files can be scheduled for deletion at the next reboot.
+const rebootDeleteSupported = false
+
+// scheduleDeleteOnReboot is unavailable outside Windows.
+func scheduleDeleteOnReboot(path string) error {
+	return errors.New("delete-on-reboot is only available on Windows")
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package tasker
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const movefileDelayUntilReboot = 0x4
+
+var procMoveFileExW = syscall.NewLazyDLL("kernel32.dll").NewProc("MoveFileExW")
+
+// rebootDeleteSupported reports whether files can be scheduled for deletion at the next reboot.
+const rebootDeleteSupported = true
+
+// scheduleDeleteOnReboot asks Windows to delete the file early in the next boot, before any
+// program can open it again. It needs administrative rights.
+func scheduleDeleteOnReboot(path string) error {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return err
+	}
+	if r, _, err := procMoveFileExW.Call(uintptr(unsafe.Pointer(p)), 0, movefileDelayUntilReboot); r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package tasker
+
+import (
+	"html/template"
+	"os"
+	"sort"
+	"time"
+)
+
+// chartBarWidth is the width in pixels of the longest bar in a report chart.
+const chartBarWidth = 400
+
+// chartBar is one bar of an SVG bar chart in the HTML report.
+type chartBar struct {
+	Label string
+	Value int64
+	Width int
+	Y     int
+}
+
+// reportChart is a titled horizontal bar chart.
+type reportChart struct {
+	Title  string
+	Bars   []chartBar
+	Height int
+}
+
+// reportRoot is the summary row of one directory.
+type reportRoot struct {
+	Path    string
+	Deleted int
+	Bytes   ByteSize
+	Failed  int
+	Error   string
+}
+
+// htmlReport is the data rendered by reportTemplate.
+type htmlReport struct {
+	Generated string
+	Roots     []reportRoot
+	Charts    []reportChart
+	Failures  []string
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Cleanup report {{.Generated}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: left; }
+td.num { text-align: right; }
+.failed { color: #b00; }
+svg text { font-size: 12px; }
+</style>
+</head>
+<body>
+<h1>Cleanup report</h1>
+<p>Generated {{.Generated}}</p>
+<h2>Directories</h2>
+<table>
+<tr><th>Directory</th><th>Files deleted</th><th>Bytes reclaimed</th><th>Failures</th><th>Status</th></tr>
+{{range .Roots}}<tr><td>{{.Path}}</td><td class="num">{{.Deleted}}</td><td class="num">{{.Bytes}}</td><td class="num">{{.Failed}}</td><td{{if .Error}} class="failed"{{end}}>{{if .Error}}{{.Error}}{{else}}ok{{end}}</td></tr>
+{{end}}</table>
+{{range .Charts}}<h2>{{.Title}}</h2>
+{{if .Bars}}<svg width="760" height="{{.Height}}" xmlns="http://www.w3.org/2000/svg">
+{{range .Bars}}<text x="0" y="{{.Y}}" dy="14">{{.Label}}</text>
+<rect x="240" y="{{.Y}}" width="{{.Width}}" height="18" fill="#4a7ab5"></rect>
+<text x="{{.Width}}" y="{{.Y}}" dx="246" dy="14">{{.Value}}</text>
+{{end}}</svg>
+{{else}}<p>Nothing to show.</p>
+{{end}}{{end}}
+{{if .Failures}}<h2>Failed files</h2>
+<ul>
+{{range .Failures}}<li class="failed">{{.}}</li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`))
+
+// newReportChart builds a chart from labelled values, largest first, omitting zero values.
+func newReportChart(title string, values map[string]int64) reportChart {
+	labels := make([]string, 0, len(values))
+	var largest int64
+	for label, v := range values {
+		if v <= 0 {
+			continue
+		}
+		labels = append(labels, label)
+		if v > largest {
+			largest = v
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if values[labels[i]] != values[labels[j]] {
+			return values[labels[i]] > values[labels[j]]
+		}
+		return labels[i] < labels[j]
+	})
+
+	chart := reportChart{Title: title}
+	for i, label := range labels {
+		chart.Bars = append(chart.Bars, chartBar{
+			Label: label,
+			Value: values[label],
+			Width: int(values[label] * chartBarWidth / largest),
+			Y:     i * 24,
+		})
+	}
+	chart.Height = len(chart.Bars) * 24
+	return chart
+}
+
+// WriteHTMLReport renders the per-directory outcome of a run, with charts of reclaimed space by
+// extension and by directory and of failures by directory.
+func WriteHTMLReport(path string, roots []string, stats []*RunStats, errs []error) error {
+	report := htmlReport{Generated: time.Now().Format(time.RFC1123)}
+	byExt := make(map[string]int64)
+	byDir := make(map[string]int64)
+	failuresByDir := make(map[string]int64)
+
+	for i, root := range roots {
+		record := stats[i].Record()
+		row := reportRoot{Path: root, Deleted: record.Deleted, Bytes: ByteSize(record.Bytes), Failed: record.Failed}
+		if errs[i] != nil {
+			row.Error = errs[i].Error()
+		}
+		report.Roots = append(report.Roots, row)
+
+		for ext, n := range stats[i].BytesByExtension() {
+			byExt[ext] += n
+		}
+		byDir[root] += record.Bytes
+		failuresByDir[root] += int64(record.Failed)
+		report.Failures = append(report.Failures, stats[i].FailedPaths()...)
+	}
+
+	report.Charts = []reportChart{
+		newReportChart("Bytes reclaimed by extension", byExt),
+		newReportChart("Bytes reclaimed by directory", byDir),
+		newReportChart("Failures by directory", failuresByDir),
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := reportTemplate.Execute(f, report); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,125 @@
+package tasker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Phase is the stage a Run has reached.
+type Phase int
+
+const (
+	PhaseScanning Phase = iota
+	PhaseDeleting
+	PhaseFinalizing
+	PhaseDone
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseScanning:
+		return "scanning"
+	case PhaseDeleting:
+		return "deleting"
+	case PhaseFinalizing:
+		return "finalizing"
+	case PhaseDone:
+		return "done"
+	}
+	return fmt.Sprintf("Phase(%d)", int(p))
+}
+
+// Run is a cleanup of one directory started by Engine.Start. Its accessors are safe to call from
+// any goroutine while the run is in progress, e.g. to drive a progress display.
+type Run struct {
+	Dir string
+
+	stats  *RunStats
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu    sync.Mutex
+	phase Phase
+	err   error
+}
+
+// Start begins cleaning dirPath in the background and returns immediately.
+func (e *Engine) Start(dirPath string) *Run {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Run{Dir: dirPath, stats: &RunStats{}, cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer close(r.done)
+		defer cancel()
+		if err := refuseDryRunOnly(dirPath); err != nil {
+			r.finish(err)
+			return
+		}
+		deleter := e.deleter.forRun(r.stats)
+
+		files, err := deleter.fs().ReadDir(dirPath)
+		if err != nil {
+			r.finish(fmt.Errorf("reading directory: %v", err))
+			return
+		}
+		r.setPhase(PhaseDeleting)
+		err = deleter.deleteFiles(ctx, dirPath, files, e.workers, e.retries, e.timeout)
+		r.setPhase(PhaseFinalizing)
+		if err == nil {
+			err = ctx.Err()
+		}
+		r.finish(err)
+	}()
+	return r
+}
+
+func (r *Run) setPhase(p Phase) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.phase = p
+}
+
+func (r *Run) finish(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.phase = PhaseDone
+	r.err = err
+}
+
+// Phase returns the stage the run has reached.
+func (r *Run) Phase() Phase {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.phase
+}
+
+// Stats returns the run's counters so far; they are final once the run is done.
+func (r *Run) Stats() RunRecord {
+	return r.stats.Record()
+}
+
+// Cancel stops the run: no further files are deleted. Wait still blocks until deletions already in
+// progress have finished.
+func (r *Run) Cancel() {
+	r.cancel()
+}
+
+// Done returns a channel closed when the run has finished.
+func (r *Run) Done() <-chan struct{} {
+	return r.done
+}
+
+// Wait blocks until the run has finished and returns its error.
+func (r *Run) Wait() error {
+	<-r.done
+	return r.Err()
+}
+
+// Err returns the error the run finished with, or nil while it is still in progress. A cancelled
+// run reports context.Canceled.
+func (r *Run) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
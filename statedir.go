@@ -0,0 +1,158 @@
+package tasker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// stateMu serialises read-modify-write updates of state files shared by runs in one process.
+var stateMu sync.Mutex
+
+// stateDirEnv overrides the location of the state directory.
+const stateDirEnv = "TASKER_STATE_DIR"
+
+// StateDir returns the directory holding the tool's persistent state (run history, directory
+// fingerprints, locks), creating it if needed. It defaults to a folder under the user's
+// configuration directory and can be moved with the TASKER_STATE_DIR environment variable.
+func StateDir() (string, error) {
+	dir := os.Getenv(stateDirEnv)
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(base, "file_delete_tasker")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// statePath returns the path of a named file in the state directory.
+func statePath(name string) (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// corruptStateError reports a state file that exists but cannot be decoded.
+type corruptStateError struct {
+	path string
+	err  error
+}
+
+func (e *corruptStateError) Error() string {
+	return fmt.Sprintf("corrupt state file %s: %v", e.path, e.err)
+}
+
+// readState decodes a JSON state file into v, leaving v untouched when the file does not exist.
+// A file that cannot be decoded, e.g. after a crash on a filesystem without atomic renames, is
+// moved aside for inspection and treated as empty so runs are never blocked by damaged state.
+func readState(name string, v any) error {
+	err := readStateStrict(name, v)
+	var corrupt *corruptStateError
+	if !errors.As(err, &corrupt) {
+		return err
+	}
+	aside := fmt.Sprintf("%s.corrupt-%s", corrupt.path, time.Now().UTC().Format("20060102T150405Z"))
+	if rerr := os.Rename(corrupt.path, aside); rerr != nil {
+		return err
+	}
+	fmt.Printf("Warning: corrupt state file %s moved to %s, starting afresh\n", corrupt.path, aside)
+	pruneCorruptCopies(corrupt.path)
+	return nil
+}
+
+// corruptCopies is how many moved-aside copies of each state file are kept for inspection.
+const corruptCopies = 3
+
+// pruneCorruptCopies removes all but the newest moved-aside copies of a state file. Their names
+// end in a UTC timestamp, so they sort by age.
+func pruneCorruptCopies(path string) {
+	copies, err := filepath.Glob(path + ".corrupt-*")
+	if err != nil || len(copies) <= corruptCopies {
+		return
+	}
+	sort.Strings(copies)
+	for _, old := range copies[:len(copies)-corruptCopies] {
+		os.Remove(old)
+	}
+}
+
+// readStateStrict is readState for state a safety check depends on, where starting afresh would
+// disable the check: a file that cannot be decoded is left in place and reported as a
+// *corruptStateError.
+func readStateStrict(name string, v any) error {
+	path, err := statePath(name)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return &corruptStateError{path: path, err: err}
+	}
+	return nil
+}
+
+// writeState encodes v as JSON and atomically replaces the named state file.
+func writeState(name string, v any) error {
+	path, err := statePath(name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0o600)
+}
+
+// writeFileAtomic writes data to a temporary file next to path, flushes it to disk, and renames it
+// over path, so readers see either the old or the new contents but never a partial write.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package tasker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadStateKeepsNewestCorruptCopies(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(stateDirEnv, dir)
+	path := filepath.Join(dir, historyStateFile)
+	for _, stamp := range []string{"20260101T000000Z", "20260201T000000Z", "20260301T000000Z", "20260401T000000Z"} {
+		if err := os.WriteFile(path+".corrupt-"+stamp, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var v map[string]any
+	if err := readState(historyStateFile, &v); err != nil {
+		t.Fatal(err)
+	}
+	copies, err := filepath.Glob(path + ".corrupt-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(copies) != corruptCopies {
+		t.Fatalf("%d corrupt copies left, want %d: %v", len(copies), corruptCopies, copies)
+	}
+	for _, old := range []string{"20260101T000000Z", "20260201T000000Z"} {
+		if _, err := os.Stat(path + ".corrupt-" + old); !os.IsNotExist(err) {
+			t.Errorf("copy from %s was kept", old)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package tasker
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RunStats counts what a run did in one directory. It is safe for concurrent use, and a nil
+// *RunStats silently discards updates.
+type RunStats struct {
+	mu      sync.Mutex
+	deleted int
+	gone    int
+	bytes   int64
+	failed  []string
+	byExt   map[string]int64
+	retries map[string]int
+}
+
+// AddDeleted records a successfully deleted file of the given size.
+func (s *RunStats) AddDeleted(path string, size int64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleted++
+	s.bytes += size
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" {
+		ext = "(none)"
+	}
+	if s.byExt == nil {
+		s.byExt = make(map[string]int64)
+	}
+	s.byExt[ext] += size
+}
+
+// AddGone records a matching file that another process removed before this run could.
+func (s *RunStats) AddGone(path string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gone++
+}
+
+// AddRetry records a failed attempt that will be retried, by error class.
+func (s *RunStats) AddRetry(class string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.retries == nil {
+		s.retries = make(map[string]int)
+	}
+	s.retries[class]++
+}
+
+// AddFailed records a file that could not be deleted.
+func (s *RunStats) AddFailed(path string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failed = append(s.failed, path)
+}
+
+// BytesByExtension returns a copy of the reclaimed bytes keyed by lower-case file extension.
+func (s *RunStats) BytesByExtension() map[string]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	byExt := make(map[string]int64, len(s.byExt))
+	for ext, n := range s.byExt {
+		byExt[ext] = n
+	}
+	return byExt
+}
+
+// FailedPaths returns a copy of the paths that could not be deleted.
+func (s *RunStats) FailedPaths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.failed...)
+}
+
+// Record returns a point-in-time copy of the counters.
+func (s *RunStats) Record() RunRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := RunRecord{
+		Time:    time.Now().UTC(),
+		Deleted: s.deleted,
+		Gone:    s.gone,
+		Bytes:   s.bytes,
+		Failed:  len(s.failed),
+	}
+	if len(s.retries) > 0 {
+		record.Retries = make(map[string]int, len(s.retries))
+		for class, n := range s.retries {
+			record.Retries[class] = n
+		}
+	}
+	if len(s.failed) > 0 {
+		record.FailureStreaks = make(map[string]int, len(s.failed))
+		for _, path := range s.failed {
+			record.FailureStreaks[path] = 1
+		}
+	}
+	return record
+}
+
+// RunRecord is the persisted outcome of a run against one directory.
+type RunRecord struct {
+	Time    time.Time `json:"time"`
+	Deleted int       `json:"deleted"`
+	Gone    int       `json:"gone,omitempty"`
+	Bytes   int64     `json:"bytes"`
+	Failed  int       `json:"failed"`
+	Note    string    `json:"note,omitempty"`
+
+	// Retries counts failed attempts that were retried, keyed by error class.
+	Retries map[string]int `json:"retries,omitempty"`
+
+	// FailureStreaks maps each path that failed in this run to the number of consecutive runs it has failed in.
+	FailureStreaks map[string]int `json:"failure_streaks,omitempty"`
+}
@@ -0,0 +1,1109 @@
+// Package tasker deletes files with a given extension from directories, with retries, filters
+// and safety checks. The file_delete_tasker command is a thin wrapper around Application; Engine
+// is the API for embedding the same cleanup in other programs.
+package tasker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DirectoryValidator handles directory validation logic
+type DirectoryValidator struct{}
+
+// Validate checks if the directory exists and prompts the user for a valid path if it doesn't.
+func (dv *DirectoryValidator) Validate(dirPath string) (string, error) {
+	const maxRetries = 3
+	reader := bufio.NewReader(os.Stdin)
+
+	for i := 0; i < maxRetries; i++ {
+		if _, err := os.Stat(dirPath); err == nil {
+			return dirPath, nil
+		}
+
+		fmt.Println("Invalid directory. Please enter a valid directory path:")
+		newPath, _ := reader.ReadString('\n')
+		dirPath = strings.TrimSpace(newPath)
+	}
+
+	return "", errors.New("maximum retries reached for directory validation")
+}
+
+// FileDeleter handles file deletion logic. Once configured, a FileDeleter may be shared by
+// goroutines cleaning different directories: deletions only read its settings, and per-run
+// filters and statistics belong to the copy returned by forRun.
+type FileDeleter struct {
+	Extension  string
+	NameForm   NameForm
+	FoldCase   bool
+	FollowUps  FollowUps
+	Filters    []NamedFilter
+	ReportKept bool
+	Stats      *RunStats
+	Throttle   *LoadThrottle
+
+	// FS and Log default to the local filesystem and standard output when nil.
+	FS    FS
+	Log   io.Writer
+	hooks *hooks
+}
+
+// NamedFilter pairs a filter with the option that configured it, so kept files can be explained.
+type NamedFilter struct {
+	Name   string
+	Filter FileFilter
+
+	// Safety filters protect a file in its own right, such as one without a backup copy. Unlike
+	// filters choosing what to delete, they also apply to files deleted alongside a match by -group.
+	Safety bool
+}
+
+// AddFilter appends a filter every file must pass before it is deleted.
+func (fd *FileDeleter) AddFilter(name string, filter FileFilter) {
+	fd.Filters = append(fd.Filters, NamedFilter{Name: name, Filter: filter})
+}
+
+// AddSafetyFilter appends a safety filter every file must pass before it is deleted, whether it
+// matches or is part of a group.
+func (fd *FileDeleter) AddSafetyFilter(name string, filter FileFilter) {
+	fd.Filters = append(fd.Filters, NamedFilter{Name: name, Filter: filter, Safety: true})
+}
+
+// forRun returns a copy of fd for a single run, recording into stats. Filters added to the copy
+// do not affect fd or other runs.
+func (fd *FileDeleter) forRun(stats *RunStats) *FileDeleter {
+	run := *fd
+	run.Filters = append([]NamedFilter(nil), fd.Filters...)
+	run.Stats = stats
+	return &run
+}
+
+// fs returns the filesystem files are deleted from.
+func (fd *FileDeleter) fs() FS {
+	if fd.FS == nil {
+		return osFS{}
+	}
+	return fd.FS
+}
+
+// logf writes a progress message to the deleter's log.
+func (fd *FileDeleter) logf(format string, args ...any) {
+	if fd.Log == nil {
+		fmt.Printf(format, args...)
+		return
+	}
+	fmt.Fprintf(fd.Log, format, args...)
+}
+
+// nameKey returns the form of a name used to compare it with extensions and other names.
+func (fd *FileDeleter) nameKey(name string) string {
+	name = fd.NameForm.normalize(name)
+	if fd.FoldCase {
+		name = strings.ToLower(name)
+	}
+	return name
+}
+
+// matches reports whether the entry has the target extension and passes every filter.
+func (fd *FileDeleter) matches(dirPath string, file os.DirEntry) bool {
+	path := filepath.Join(dirPath, file.Name())
+	reason := fd.keepReason(dirPath, file)
+	if reason != "" && fd.ReportKept {
+		fd.logf("Kept file: %s (%s)\n", path, reason)
+	}
+	if reason == "" {
+		fd.hooks.match(path)
+	} else {
+		fd.hooks.skip(path, reason)
+	}
+	return reason == ""
+}
+
+// keepReason returns why the entry is retained, or an empty string if it should be deleted.
+func (fd *FileDeleter) keepReason(dirPath string, file os.DirEntry) string {
+	if file.IsDir() {
+		return "directory"
+	}
+	if file.Name() == lockFileName {
+		return "lock file"
+	}
+	if windowsNames {
+		if problem := win32NameProblem(file.Name()); problem != "" {
+			return problem
+		}
+	}
+	if !strings.HasSuffix(fd.nameKey(file.Name()), fd.nameKey(fd.Extension)) {
+		return "extension does not match " + fd.Extension
+	}
+
+	return fd.filterReason(filepath.Join(dirPath, file.Name()), false)
+}
+
+// safetyReason returns why a file deleted alongside a matching one must be kept, or an empty
+// string. Only protections and safety filters apply; the file need not match itself.
+func (fd *FileDeleter) safetyReason(dirPath string, file os.DirEntry) string {
+	if file.Name() == lockFileName {
+		return "lock file"
+	}
+	if windowsNames {
+		if problem := win32NameProblem(file.Name()); problem != "" {
+			return problem
+		}
+	}
+	return fd.filterReason(filepath.Join(dirPath, file.Name()), true)
+}
+
+// filterReason runs the filters, or only the safety filters, and explains the first that rejects
+// filePath.
+func (fd *FileDeleter) filterReason(filePath string, safetyOnly bool) string {
+	for _, nf := range fd.Filters {
+		if safetyOnly && !nf.Safety {
+			continue
+		}
+		ok, err := nf.Filter(filePath)
+		if errors.Is(err, os.ErrNotExist) {
+			return "already gone"
+		}
+		if err != nil {
+			fd.logf("Skipping file: %s, %v\n", filePath, err)
+			return fmt.Sprintf("%s failed: %v", nf.Name, err)
+		}
+		if !ok {
+			return "excluded by " + nf.Name
+		}
+	}
+	return ""
+}
+
+// DeleteFilesWithTimeout deletes files with a timeout and retries on failure.
+func (fd *FileDeleter) DeleteFilesWithTimeout(dirPath string, files []os.DirEntry, workerCount, maxRetries int, timeout time.Duration) error {
+	return fd.deleteFiles(context.Background(), dirPath, files, workerCount, maxRetries, timeout)
+}
+
+// deleteFiles implements DeleteFilesWithTimeout. Once runCtx is cancelled no further files are
+// matched or deleted, though removals already in progress complete.
+func (fd *FileDeleter) deleteFiles(runCtx context.Context, dirPath string, files []os.DirEntry, workerCount, maxRetries int, timeout time.Duration) error {
+	type FileTask struct {
+		FileName string
+		Size     int64
+		Retries  int
+	}
+
+	fileChan := make(chan FileTask, len(files))
+	errorChan := make(chan error, len(files))
+	var wg sync.WaitGroup
+
+	// pending counts tasks not yet finished, including ones queued again for a retry, so the
+	// channel is only closed once no worker can send to it anymore.
+	var pending sync.WaitGroup
+
+	// retrying is the retry queue depth: tasks queued again after a failed attempt.
+	var retrying atomic.Int64
+	requeue := func(task FileTask, filePath string, cause error) {
+		class := errorClass(cause)
+		fd.Stats.AddRetry(class)
+		task.Retries++
+		depth := retrying.Add(1)
+		fd.logf("Retrying file: %s (%s, attempt %d of %d, %d queued for retry)\n", filePath, class, task.Retries+1, maxRetries+1, depth)
+		fileChan <- task
+	}
+
+	// Worker function
+	worker := func() {
+		defer wg.Done()
+		for task := range fileChan {
+			if task.Retries > 0 {
+				retrying.Add(-1)
+			}
+			if runCtx.Err() != nil {
+				pending.Done()
+				continue
+			}
+			// Wait before the timeout starts so a paused deletion isn't counted as timed out.
+			fd.Throttle.Wait()
+			ctx, cancel := context.WithTimeout(runCtx, timeout)
+
+			filePath := filepath.Join(dirPath, task.FileName)
+			errChan := make(chan error, 1)
+
+			// Attempt to delete the file
+			go func() {
+				errChan <- fd.fs().Remove(filePath)
+			}()
+
+			// cancel is called as soon as the attempt settles; deferred, every task's timer would be
+			// held until the worker exits.
+			select {
+			case <-ctx.Done():
+				cancel()
+				// Timeout occurred, unless the whole run was cancelled
+				if runCtx.Err() != nil {
+					pending.Done()
+					continue
+				}
+				if task.Retries < maxRetries {
+					requeue(task, filePath, ctx.Err())
+				} else {
+					fd.Stats.AddFailed(filePath)
+					fd.FollowUps.run(filePath, ctx.Err())
+					err := fmt.Errorf("timeout deleting file after %d retries: %s", maxRetries, filePath)
+					fd.hooks.fail(filePath, err)
+					errorChan <- err
+					pending.Done()
+				}
+			case err := <-errChan:
+				cancel()
+				// File deletion completed
+				if errors.Is(err, os.ErrNotExist) {
+					// Another process got there first, or a timed-out attempt completed after all.
+					if task.Retries > 0 {
+						err = nil
+					} else {
+						fd.Stats.AddGone(filePath)
+						fd.hooks.skip(filePath, "already gone")
+						fd.logf("Already gone: %s\n", filePath)
+						pending.Done()
+						continue
+					}
+				}
+				if err != nil {
+					if task.Retries < maxRetries {
+						requeue(task, filePath, err)
+					} else {
+						fd.Stats.AddFailed(filePath)
+						fd.FollowUps.run(filePath, err)
+						err = fmt.Errorf("failed to delete file after %d retries: %s, %v", maxRetries, filePath, err)
+						fd.hooks.fail(filePath, err)
+						errorChan <- err
+						pending.Done()
+					}
+				} else {
+					fd.Stats.AddDeleted(filePath, task.Size)
+					fd.hooks.delete(filePath, task.Size)
+					fd.logf("Deleted file: %s\n", filePath)
+					pending.Done()
+				}
+			}
+		}
+	}
+
+	// Start worker goroutines
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go worker()
+	}
+
+	// Send initial file tasks to the channel
+	go func() {
+		for _, file := range files {
+			if runCtx.Err() != nil {
+				break
+			}
+			if fd.matches(dirPath, file) {
+				var size int64
+				if info, err := file.Info(); err == nil {
+					size = info.Size()
+				}
+				pending.Add(1)
+				fileChan <- FileTask{FileName: file.Name(), Size: size, Retries: 0}
+			}
+		}
+		pending.Wait()
+		close(fileChan)
+	}()
+
+	// Wait for all workers to finish
+	wg.Wait()
+	close(errorChan)
+
+	// Collect errors
+	var errors []string
+	for err := range errorChan {
+		errors = append(errors, err.Error())
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("errors occurred during file deletion: %s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+// Application orchestrates the logic
+type Application struct {
+	Validator *DirectoryValidator
+	Deleter   *FileDeleter
+}
+
+// ManifestEntry describes a location the application may touch and the action taken there
+type ManifestEntry struct {
+	Path       string   `json:"path"`
+	Pattern    string   `json:"pattern"`
+	Recursive  bool     `json:"recursive"`
+	Action     string   `json:"action"`
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+// printManifest prints a machine-readable declaration of everything a run with the same options and
+// deleter against roots could delete or modify.
+func (app *Application) printManifest(deleter *FileDeleter, roots []string, opts *RunOptions) {
+	dryRunOnly, err := LoadDryRunOnly()
+	if err != nil {
+		fmt.Println("Error reading dry-run-only paths:", err)
+		return
+	}
+
+	action := "delete"
+	switch {
+	case opts.Plan != nil:
+		action = "none"
+	case opts.ArchiveAction != "":
+		action = opts.ArchiveAction + " archive bit"
+	}
+
+	// Every condition narrows what is touched; a file must satisfy all of them.
+	var conditions []string
+	for _, f := range deleter.Filters {
+		conditions = append(conditions, f.Name)
+	}
+	if opts.Orphans {
+		conditions = append(conditions, "-orphans: primary file missing")
+	}
+	if opts.BackupDir != "" {
+		conditions = append(conditions, "-backup-dir: identical copy in "+opts.BackupDir)
+	}
+	if opts.WarnGrace > 0 {
+		conditions = append(conditions, "-warn-grace: announced at least "+Period(opts.WarnGrace).String()+" earlier")
+	}
+	if opts.SuspectNewer > 0 {
+		conditions = append(conditions, "-suspect-newer: not modified within "+Period(opts.SuspectNewer).String())
+	}
+	if opts.SuspectLarger > 0 {
+		conditions = append(conditions, "-suspect-larger: not larger than "+ByteSize(opts.SuspectLarger).String())
+	}
+	if opts.OwnerQuota > 0 {
+		conditions = append(conditions, "-owner-quota: oldest files of owners above "+ByteSize(opts.OwnerQuota).String())
+	}
+
+	var entries []ManifestEntry
+	for _, root := range roots {
+		absPath, err := filepath.Abs(root)
+		if err != nil {
+			fmt.Println("Error resolving directory:", err)
+			return
+		}
+		rootAction := action
+		if dryRunOnly.Protects(absPath) {
+			rootAction = "none"
+		}
+		entries = append(entries, ManifestEntry{
+			Path:       absPath,
+			Pattern:    "*" + deleter.Extension,
+			Recursive:  false,
+			Action:     rootAction,
+			Conditions: conditions,
+		})
+		// Groups take every file sharing a basename with a matching file along with it.
+		if opts.Group {
+			entries = append(entries, ManifestEntry{
+				Path:       absPath,
+				Pattern:    "*",
+				Recursive:  false,
+				Action:     rootAction,
+				Conditions: append([]string{"-group: shares a basename with a matching *" + deleter.Extension + " file"}, conditions...),
+			})
+		}
+	}
+
+	out, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		fmt.Println("Error encoding manifest:", err)
+		return
+	}
+	fmt.Println(string(out))
+}
+
+// PermissionReport describes what the running account may do in a directory
+type PermissionReport struct {
+	Path      string
+	CanList   bool
+	CanDelete bool
+	Err       error
+}
+
+// NeedsElevation reports whether a run would fail without additional privileges.
+func (pr PermissionReport) NeedsElevation() bool {
+	return !pr.CanList || !pr.CanDelete
+}
+
+// CheckPermissions probes the directory for list and delete access without touching existing files.
+func CheckPermissions(dirPath string) PermissionReport {
+	report := PermissionReport{Path: dirPath}
+
+	if _, err := os.ReadDir(dirPath); err != nil {
+		report.Err = err
+		return report
+	}
+	report.CanList = true
+
+	// Deleting an entry requires write access to the directory itself,
+	// so creating and removing a probe file is a faithful check.
+	probe, err := os.CreateTemp(dirPath, ".tasker-probe-*")
+	if err != nil {
+		report.Err = err
+		return report
+	}
+	probe.Close()
+	if err := os.Remove(probe.Name()); err != nil {
+		report.Err = err
+		return report
+	}
+	report.CanDelete = true
+
+	return report
+}
+
+// Preflight prints the effective permissions the running account has on the given directory.
+func (app *Application) Preflight(args []string) {
+	if len(args) != 1 {
+		fmt.Println("Usage: <program> preflight <directory_path>")
+		return
+	}
+
+	dirPath, err := ExpandPath(args[0], time.Now())
+	if err != nil {
+		fmt.Println("Error expanding directory path:", err)
+		return
+	}
+
+	yesNo := func(ok bool) string {
+		if ok {
+			return "yes"
+		}
+		return "no"
+	}
+
+	for _, root := range ExpandBraces(dirPath) {
+		report := CheckPermissions(root)
+		fmt.Printf("Directory: %s\n", report.Path)
+		fmt.Printf("  Can list:   %s\n", yesNo(report.CanList))
+		fmt.Printf("  Can delete: %s\n", yesNo(report.CanDelete))
+		if report.NeedsElevation() {
+			fmt.Println("  Blocked by:", report.Err)
+		}
+	}
+}
+
+// isFlagSet reports whether the named flag was given on the command line.
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	found := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			found = true
+		}
+	})
+	return found
+}
+
+// RunOptions holds the per-directory settings parsed from the command line
+type RunOptions struct {
+	Orphans           bool
+	Group             bool
+	ArchiveAction     string
+	SetArchive        bool
+	WarnGrace         time.Duration
+	OwnerQuota        int64
+	SuspectNewer      time.Duration
+	SuspectLarger     int64
+	ConfirmQuarantine bool
+	MaxChange         float64
+	MaxPercent        float64
+	AcceptChanges     bool
+	ExpectMount       bool
+	AlertAfter        int
+	Note              string
+	LowMemory         bool
+	BackupDir         string
+	VerifyWorkers     int
+	CaseMode          string
+	Lock              bool
+	LockStale         time.Duration
+
+	// Plan, when set, turns the run into a dry run collecting the files that would be deleted.
+	Plan *Plan
+}
+
+// planDirectory adds the files a real run would delete from validDir to the plan, deleting nothing.
+func planDirectory(deleter *FileDeleter, validDir string, files []os.DirEntry, plan *Plan) error {
+	absDir, err := filepath.Abs(validDir)
+	if err != nil {
+		return fmt.Errorf("resolving directory: %v", err)
+	}
+
+	var planned []string
+	for _, file := range files {
+		if deleter.matches(validDir, file) {
+			planned = append(planned, filepath.Join(absDir, file.Name()))
+		}
+	}
+	plan.add(absDir, planned)
+	fmt.Printf("Planned %d files for deletion.\n", len(planned))
+	return nil
+}
+
+// warnShadowedDirectory points out when a subcommand name is also a directory in the working
+// directory. Subcommands win because they delete nothing; the directory can still be cleaned by
+// writing it as a path, such as ./doctor.
+func warnShadowedDirectory(name string) {
+	if info, err := os.Stat(name); err == nil && info.IsDir() {
+		fmt.Printf("Running the %s subcommand; to clean the directory of that name, use .%c%s\n", name, filepath.Separator, name)
+	}
+}
+
+// Run executes the application logic
+func (app *Application) Run(args []string) {
+	if len(args) > 0 {
+		switch args[0] {
+		case "manifest", "preflight", "diff-plan", "capabilities", "doctor", "replay":
+			warnShadowedDirectory(args[0])
+		}
+		switch args[0] {
+		case "preflight":
+			app.Preflight(args[1:])
+			return
+		case "diff-plan":
+			app.DiffPlan(args[1:])
+			return
+		case "capabilities":
+			app.Capabilities(args[1:])
+			return
+		case "doctor":
+			app.Doctor(args[1:])
+			return
+		case "replay":
+			app.Replay(args[1:])
+			return
+		}
+	}
+
+	// The manifest is built from the same options a run is given, so it declares what that run does.
+	manifest := len(args) > 0 && args[0] == "manifest"
+	if manifest {
+		args = args[1:]
+	}
+
+	var opts RunOptions
+	flags := flag.NewFlagSet("tasker", flag.ContinueOnError)
+	extension := flags.String("ext", app.Deleter.Extension, "file `extension` to delete")
+	imageBelow := flags.String("image-below", "", "only delete images smaller than `WIDTHxHEIGHT`")
+	archiveOnly := flags.String("archive-only", "", "only delete archives that are empty or contain nothing but files with this `extension`")
+	broken := flags.Bool("broken", false, "only delete zero-byte files and files failing a format validity check")
+	flags.BoolVar(&opts.Orphans, "orphans", false, "treat -ext as a sidecar extension and only delete sidecars whose primary file is missing")
+	flags.BoolVar(&opts.Group, "group", false, "delete files sharing a basename with a matching file together, or not at all")
+	requireBackup := flags.Bool("require-backup", false, "skip files not yet backed up (archive bit set, Windows only)")
+	archiveBit := flags.String("archive-bit", "", "only delete files whose archive bit is `set|clear` (Windows only)")
+	flags.StringVar(&opts.ArchiveAction, "archive-action", "", "instead of deleting, `set|clear` the archive bit of matching files (Windows only)")
+	flags.Var((*Period)(&opts.WarnGrace), "warn-grace", "announce matching files in a marker file and only delete them after this grace `period`, e.g. 36h or 2w")
+	flags.Var((*ByteSize)(&opts.OwnerQuota), "owner-quota", "delete each owner's oldest matching files until they use at most this `size`, e.g. 10GiB (Unix only)")
+	keepReport := flags.Bool("keep-report", false, "list every retained file with the rule that protected it")
+	flags.Var((*Period)(&opts.SuspectNewer), "suspect-newer", "quarantine matching files modified within this `period` instead of deleting them")
+	flags.Var((*ByteSize)(&opts.SuspectLarger), "suspect-larger", "quarantine matching files larger than this `size` instead of deleting them")
+	flags.BoolVar(&opts.ConfirmQuarantine, "confirm-quarantine", false, "delete files listed in the directory's quarantine list")
+	flags.Float64Var(&opts.MaxChange, "max-change", 0, "refuse to run when the directory's entries changed by more than this `percent` since the last run")
+	flags.Float64Var(&opts.MaxPercent, "max-percent", 0, "refuse to delete more than this `percent` of a directory's files in one run")
+	flags.BoolVar(&opts.AcceptChanges, "accept-changes", false, "run even if the directory changed beyond -max-change or the run exceeds -max-percent")
+	flags.BoolVar(&opts.ExpectMount, "expect-mount", false, "refuse to run unless the directory is an active mount point")
+	flags.IntVar(&opts.AlertAfter, "alert-after", 3, "alert when a file has failed deletion in this many consecutive `runs` (0 disables)")
+	flags.StringVar(&opts.Note, "note", "", "free-text `note` stored with the run history, e.g. a ticket reference")
+	planPath := flags.String("plan", "", "dry run: write the files that would be deleted to this JSON `file` and delete nothing")
+	htmlPath := flags.String("html", "", "write an HTML report with charts of the run to this `file`")
+	followUps := FollowUps{}
+	flags.Var(followUps, "on-failure", "on files still failing with `class=action`, e.g. permission=queue:elevate.txt (repeatable)")
+	deleteOnReboot := flags.Bool("delete-on-reboot", false, "schedule files still in use for deletion at the next reboot, same as -on-failure in-use=reboot (Windows only)")
+	deadLetter := flags.String("dead-letter", "", "write the paths of files that could not be deleted to this `file`, one per line")
+	deadLetterNul := flags.Bool("dead-letter-nul", false, "terminate -dead-letter paths with NUL instead of newline, for xargs -0")
+	healthcheckURL := flags.String("healthcheck", "", "ping this `URL` on start (/start), success, and failure (/fail) of the run")
+	maxLoad := flags.Float64("max-load", 0, "pause deletions while the one-minute load average per CPU exceeds this `value` (Linux only)")
+	nice := flags.Int("nice", 0, "lower the process CPU priority by this `niceness` (1-19)")
+	ioIdle := flags.Bool("io-idle", false, "run with idle IO priority (Linux ionice class, Windows background mode)")
+	flags.BoolVar(&opts.LowMemory, "low-memory", false, "stream the directory and delete with a single worker to keep memory use small")
+	mirrorOf := flags.String("mirror-of", "", "treat the target as a mirror and only delete files no longer present in this primary `directory`")
+	backupDir := flags.String("backup-dir", "", "only delete files with a byte-identical copy of the same name in this `directory`")
+	flags.IntVar(&opts.VerifyWorkers, "verify-workers", 4, "number of `workers` comparing files with their backup copies")
+	flags.BoolVar(&opts.Lock, "lock", false, "take a lock file in each directory so only one node cleans a shared directory at a time")
+	opts.LockStale = 6 * time.Hour
+	flags.Var((*Period)(&opts.LockStale), "lock-stale", "take over -lock files older than this `age`, left behind by crashed nodes (0 never does)")
+	tz := flags.String("tz", "", "evaluate path template dates in this IANA time `zone` instead of the local one")
+	holidaysPath := flags.String("holidays", "", "skip the run on dates listed in this `file` (iCalendar or one YYYY-MM-DD per line)")
+	flags.StringVar(&opts.CaseMode, "case", caseAuto, "`mode` for matching names: auto probes each directory's filesystem, sensitive, or insensitive")
+	normalize := flags.String("normalize", "nfc", "Unicode `form` file names are compared in: nfc, nfd, or none for byte-exact matching")
+	strict := flags.Bool("strict", false, "stop at the first directory that fails instead of continuing with the others")
+	if err := flags.Parse(args); err != nil {
+		return
+	}
+	args = flags.Args()
+
+	if len(args) != 1 {
+		if manifest {
+			fmt.Println("Usage: <program> manifest [options] <directory_path>")
+		} else {
+			fmt.Println("Usage: <program> [options] <directory_path>")
+		}
+		return
+	}
+	// Options configure a copy, so the Application's deleter is left as given for later and
+	// concurrent runs.
+	deleter := app.Deleter.forRun(nil)
+	deleter.Extension = *extension
+	form, err := parseNameForm(*normalize)
+	if err != nil {
+		fmt.Println("Error parsing options:", err)
+		return
+	}
+	deleter.NameForm = form
+	if _, ok := followUps[classInUse]; *deleteOnReboot && !ok {
+		if err := followUps.Set("in-use=reboot"); err != nil {
+			fmt.Println("Error parsing options:", err)
+			return
+		}
+	}
+	deleter.FollowUps = followUps
+	if opts.CaseMode, err = parseCaseMode(opts.CaseMode); err != nil {
+		fmt.Println("Error parsing options:", err)
+		return
+	}
+	deleter.ReportKept = *keepReport
+
+	// These modes need the complete listing in memory, which low-memory mode avoids.
+	if opts.LowMemory {
+		for _, name := range []string{"orphans", "group", "owner-quota", "max-change", "max-percent", "plan", "archive-action"} {
+			if isFlagSet(flags, name) {
+				fmt.Printf("Error parsing options: -%s cannot be combined with -low-memory\n", name)
+				return
+			}
+		}
+	}
+
+	if *nice < 0 || *nice > 19 {
+		fmt.Println("Error parsing options: -nice must be between 0 and 19")
+		return
+	}
+	if err := lowerPriority(*nice, *ioIdle); err != nil {
+		fmt.Println("Error lowering process priority:", err)
+		return
+	}
+
+	if *maxLoad > 0 {
+		if _, err := systemLoad(); err != nil {
+			fmt.Println("Error parsing options:", err)
+			return
+		}
+		deleter.Throttle = NewLoadThrottle(*maxLoad)
+	}
+
+	// Path templates name business dates, which differ from the server's date near midnight.
+	now := time.Now()
+	if *tz != "" {
+		loc, err := time.LoadLocation(*tz)
+		if err != nil {
+			fmt.Println("Error parsing options:", err)
+			return
+		}
+		now = now.In(loc)
+	}
+
+	var holidays HolidayCalendar
+	if *holidaysPath != "" {
+		loaded, err := LoadHolidays(*holidaysPath)
+		if err != nil {
+			fmt.Println("Error reading holiday calendar:", err)
+			return
+		}
+		holidays = loaded
+	}
+
+	// Every exit from here on counts as a failed run unless all roots were processed.
+	succeeded := false
+	if *healthcheckURL != "" && !manifest {
+		hc := NewHealthcheck(*healthcheckURL)
+		hc.Start()
+		defer func() { hc.Finish(succeeded) }()
+	}
+
+	// A deliberate skip is a successful run, so monitoring does not page anyone on a holiday.
+	if holidays.Contains(now) && !manifest {
+		fmt.Printf("Skipping run: %s is listed in the holiday calendar\n", now.Format(time.DateOnly))
+		succeeded = true
+		return
+	}
+
+	if *imageBelow != "" {
+		width, height, err := parseDimensions(*imageBelow)
+		if err != nil {
+			fmt.Println("Error parsing options:", err)
+			return
+		}
+		deleter.AddFilter("-image-below", ImageSmallerThan(width, height))
+	}
+
+	if isFlagSet(flags, "archive-only") {
+		deleter.AddFilter("-archive-only", ArchiveContainsOnly(*archiveOnly, deleter.NameForm))
+	}
+
+	if *broken {
+		deleter.AddFilter("-broken", BrokenFile)
+	}
+
+	if *mirrorOf != "" {
+		primary, err := ExpandPath(*mirrorOf, now)
+		if err != nil {
+			fmt.Println("Error expanding primary directory path:", err)
+			return
+		}
+		// A missing primary would make every mirrored file look orphaned.
+		if info, err := os.Stat(primary); err != nil || !info.IsDir() {
+			fmt.Println("Error parsing options: -mirror-of must name an existing directory")
+			return
+		}
+		deleter.AddFilter("-mirror-of", MissingFrom(primary, deleter.NameForm))
+	}
+
+	if *backupDir != "" {
+		backup, err := ExpandPath(*backupDir, now)
+		if err != nil {
+			fmt.Println("Error expanding backup directory path:", err)
+			return
+		}
+		opts.BackupDir = backup
+	}
+
+	if *requireBackup || *archiveBit != "" || opts.ArchiveAction != "" {
+		if !archiveBitSupported {
+			fmt.Println("Error parsing options: the archive attribute is only available on Windows")
+			return
+		}
+	}
+
+	if *archiveBit != "" {
+		set, err := parseArchiveBitState(*archiveBit)
+		if err != nil {
+			fmt.Println("Error parsing options:", err)
+			return
+		}
+		deleter.AddSafetyFilter("-archive-bit", ArchiveBitIs(set))
+	}
+
+	// A clear archive bit means a backup agent has already picked the file up.
+	if *requireBackup {
+		deleter.AddSafetyFilter("-require-backup", ArchiveBitIs(false))
+	}
+
+	if opts.ArchiveAction != "" {
+		set, err := parseArchiveBitState(opts.ArchiveAction)
+		if err != nil {
+			fmt.Println("Error parsing options:", err)
+			return
+		}
+		opts.SetArchive = set
+	}
+
+	dirPath, err := ExpandPath(args[0], now)
+	if err != nil {
+		fmt.Println("Error expanding directory path:", err)
+		return
+	}
+
+	if *planPath != "" {
+		opts.Plan = &Plan{Created: time.Now().UTC()}
+	}
+
+	if manifest {
+		app.printManifest(deleter, ExpandBraces(dirPath), &opts)
+		succeeded = true
+		return
+	}
+
+	if opts.Note != "" {
+		fmt.Println("Run note:", opts.Note)
+	}
+
+	// Each root is isolated from the others: a root that cannot be opened or cleaned is recorded and the
+	// run continues, unless -strict asks for the whole run to stop at the first failure.
+	roots := ExpandBraces(dirPath)
+	rootErrors := make([]error, len(roots))
+	rootStats := make([]*RunStats, len(roots))
+	for i, root := range roots {
+		if len(roots) > 1 {
+			fmt.Printf("== %s ==\n", root)
+		}
+		rootStats[i] = &RunStats{}
+		if rootErrors[i] = app.runDirectory(deleter, root, &opts, rootStats[i]); rootErrors[i] != nil {
+			fmt.Println("Error:", rootErrors[i])
+			if *strict {
+				return
+			}
+		}
+	}
+
+	succeeded = true
+	for _, err := range rootErrors {
+		if err != nil {
+			succeeded = false
+		}
+	}
+
+	if *htmlPath != "" && opts.Plan == nil {
+		if err := WriteHTMLReport(*htmlPath, roots, rootStats, rootErrors); err != nil {
+			fmt.Println("Error writing HTML report:", err)
+		} else {
+			fmt.Println("HTML report written to", *htmlPath)
+		}
+	}
+
+	if *deadLetter != "" && opts.Plan == nil {
+		if n, err := WriteDeadLetter(*deadLetter, rootStats, *deadLetterNul); err != nil {
+			fmt.Println("Error writing dead-letter file:", err)
+		} else {
+			fmt.Printf("%d failed paths written to %s\n", n, *deadLetter)
+		}
+	}
+
+	if opts.Plan != nil {
+		if err := SavePlan(*planPath, opts.Plan); err != nil {
+			fmt.Println("Error writing plan:", err)
+			return
+		}
+		fmt.Printf("Plan with %d files written to %s\n", len(opts.Plan.Files), *planPath)
+	}
+
+	if len(roots) > 1 {
+		failed := 0
+		fmt.Println("Summary:")
+		for i, root := range roots {
+			if rootErrors[i] != nil {
+				failed++
+				fmt.Printf("  %s: failed, %v\n", root, rootErrors[i])
+			} else {
+				fmt.Printf("  %s: ok\n", root)
+			}
+		}
+		fmt.Printf("%d of %d directories processed successfully.\n", len(roots)-failed, len(roots))
+	}
+}
+
+// runDirectory cleans a single directory, counting its outcome in stats. Filters that depend on the
+// directory's contents are added to a copy of the deleter so that several roots can be processed in one run.
+func (app *Application) runDirectory(deleter *FileDeleter, dirPath string, opts *RunOptions, stats *RunStats) error {
+	validDir, err := app.Validator.Validate(dirPath)
+	if err != nil {
+		return fmt.Errorf("validating directory: %v", err)
+	}
+	validDir = longPathName(validDir)
+
+	// An unmounted share leaves an empty local directory behind; never clean that by mistake.
+	if opts.ExpectMount {
+		mounted, err := isMountPoint(validDir)
+		if err != nil {
+			return fmt.Errorf("checking mount point: %v", err)
+		}
+		if !mounted {
+			return fmt.Errorf("refusing to run: %s is not a mount point", validDir)
+		}
+	}
+
+	// Dry-run-only paths are an administrator's boundary that no option can lift.
+	dryRunOnly, err := LoadDryRunOnly()
+	if err != nil {
+		return fmt.Errorf("reading dry-run-only paths: %v", err)
+	}
+	protected := dryRunOnly.Protects(validDir)
+	if protected && opts.Plan == nil {
+		fmt.Printf("%s is under a dry-run-only path, nothing will be deleted\n", validDir)
+	}
+
+	// Several nodes may see the same share; a dry run changes nothing and needs no lock.
+	if opts.Lock && opts.Plan == nil && !protected {
+		lock, err := AcquireDirectoryLock(validDir, opts.LockStale)
+		if err != nil {
+			return fmt.Errorf("locking directory: %v", err)
+		}
+		defer func() {
+			if err := lock.Release(); err != nil {
+				fmt.Println("Error releasing directory lock:", err)
+			}
+		}()
+	}
+
+	// Files scheduled for deletion at reboot by earlier runs are only confirmed once they are gone.
+	if opts.Plan == nil {
+		if err := checkRebootDeletions(validDir); err != nil {
+			fmt.Println("Error checking delete-on-reboot files:", err)
+		}
+	}
+
+	// Low-memory mode streams the directory while deleting instead of listing it up front.
+	var files []os.DirEntry
+	if !opts.LowMemory || protected {
+		if files, err = os.ReadDir(validDir); err != nil {
+			return fmt.Errorf("reading directory: %v", err)
+		}
+		fmt.Printf("Total files in directory: %d\n", len(files))
+	}
+
+	var absDir string
+	if opts.MaxChange > 0 {
+		if absDir, err = filepath.Abs(validDir); err != nil {
+			return fmt.Errorf("resolving directory: %v", err)
+		}
+		proceed, err := confirmDirectoryChange(absDir, files, opts.MaxChange, opts.AcceptChanges)
+		if err != nil {
+			return fmt.Errorf("checking directory fingerprint: %v", err)
+		}
+		if !proceed {
+			return fmt.Errorf("run not confirmed for %s", validDir)
+		}
+	}
+
+	deleter = deleter.forRun(stats)
+	switch opts.CaseMode {
+	case caseInsensitive:
+		deleter.FoldCase = true
+	case caseAuto:
+		if deleter.FoldCase, err = caseInsensitiveDir(validDir); err != nil {
+			return fmt.Errorf("probing case sensitivity: %v", err)
+		}
+	}
+
+	if opts.Orphans {
+		deleter.AddFilter("-orphans", OrphanedSidecar(files, deleter.Extension, deleter.nameKey))
+	}
+
+	// Count the selection before the backup check, quarantine and grace period narrow it, since
+	// those hold files back for later rather than deciding what the run targets.
+	if opts.MaxPercent > 0 && opts.Plan == nil {
+		matched, total := 0, 0
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+			total++
+			if deleter.keepReason(validDir, file) == "" {
+				matched++
+			}
+		}
+		if !confirmDeletionShare(validDir, matched, total, opts.MaxPercent, opts.AcceptChanges) {
+			return fmt.Errorf("run not confirmed for %s", validDir)
+		}
+	}
+
+	var verifier *BackupVerifier
+	if opts.BackupDir != "" {
+		if verifier, err = NewBackupVerifier(opts.BackupDir, opts.VerifyWorkers, deleter.NameForm); err != nil {
+			return fmt.Errorf("reading backup directory: %v", err)
+		}
+		verifier.Prepare(validDir, files, deleter.Extension)
+		deleter.AddSafetyFilter("-backup-dir", verifier.Filter)
+	}
+
+	var quarantine *Quarantine
+	if opts.SuspectNewer > 0 || opts.SuspectLarger > 0 || opts.ConfirmQuarantine {
+		if quarantine, err = NewQuarantine(validDir, opts.SuspectNewer, opts.SuspectLarger, opts.ConfirmQuarantine); err != nil {
+			return fmt.Errorf("reading quarantine list: %v", err)
+		}
+		deleter.AddSafetyFilter("quarantine", quarantine.Filter)
+	}
+
+	// The warn-then-delete policy goes last so only files passing every other filter are announced.
+	var pending *PendingDeletions
+	if opts.WarnGrace > 0 {
+		if pending, err = LoadPendingDeletions(validDir, opts.WarnGrace); err != nil {
+			return fmt.Errorf("reading pending deletions: %v", err)
+		}
+		deleter.AddSafetyFilter("-warn-grace", pending.Filter)
+	}
+
+	if opts.Plan != nil {
+		return planDirectory(deleter, validDir, files, opts.Plan)
+	}
+	if protected {
+		plan := &Plan{}
+		if err := planDirectory(deleter, validDir, files, plan); err != nil {
+			return err
+		}
+		for _, path := range plan.Files {
+			fmt.Println("Would delete file:", path)
+		}
+		return nil
+	}
+
+	switch {
+	case opts.ArchiveAction != "":
+		if err := deleter.SetArchiveBits(validDir, files, opts.SetArchive); err != nil {
+			return fmt.Errorf("updating attributes: %v", err)
+		}
+		fmt.Println("Archive bit updated on all matching files.")
+		return nil
+	case opts.LowMemory:
+		err = deleter.DeleteFilesLowMemory(validDir, defaultRetries, defaultTimeout)
+	case opts.Group:
+		err = deleter.DeleteGroups(validDir, files)
+	case opts.OwnerQuota > 0:
+		err = deleter.EnforceOwnerQuota(validDir, files, opts.OwnerQuota)
+	default:
+		err = deleter.DeleteFilesWithTimeout(validDir, files, defaultWorkers, defaultRetries, defaultTimeout)
+	}
+	if pending != nil {
+		if serr := pending.Save(); serr != nil {
+			fmt.Println("Error writing pending deletions:", serr)
+		}
+	}
+	if verifier != nil {
+		if serr := verifier.Save(); serr != nil {
+			fmt.Println("Error writing backup checksum cache:", serr)
+		}
+	}
+	if quarantine != nil {
+		if serr := quarantine.Save(); serr != nil {
+			fmt.Println("Error writing quarantine list:", serr)
+		}
+	}
+
+	record := deleter.Stats.Record()
+	record.Note = opts.Note
+	if historyKey, aerr := filepath.Abs(validDir); aerr == nil {
+		prev, hasPrev, herr := recordRun(historyKey, &record)
+		if herr != nil {
+			fmt.Println("Error recording run history:", herr)
+		} else {
+			printRunComparison(record, prev, hasPrev)
+			alertChronicFailures(record, opts.AlertAfter)
+		}
+	}
+
+	if absDir != "" {
+		if remaining, rerr := os.ReadDir(validDir); rerr == nil {
+			if serr := saveFingerprint(absDir, NewDirectoryFingerprint(remaining)); serr != nil {
+				fmt.Println("Error recording directory fingerprint:", serr)
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("deleting files: %v", err)
+	}
+
+	fmt.Println("All files with the specified extension deleted successfully.")
+	return nil
+}
+
+// DefaultExtension is used when neither -ext nor the TASKER_EXTENSION environment variable is set.
+const DefaultExtension = ".rdp"
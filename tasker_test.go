@@ -0,0 +1,56 @@
+package tasker_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	tasker "github.com/nilsonmart/file_delete_tasker"
+)
+
+// TestApplicationRunKeepsDeleter runs one Application several times, at once and in turn, and
+// checks that options given to one run do not carry over to the next. Meant to be run with -race.
+func TestApplicationRunKeepsDeleter(t *testing.T) {
+	t.Setenv("TASKER_STATE_DIR", t.TempDir())
+	app := &tasker.Application{
+		Validator: &tasker.DirectoryValidator{},
+		Deleter:   &tasker.FileDeleter{Extension: ".rdp", Log: io.Discard},
+	}
+
+	// -broken keeps the intact file, a plain run deletes it.
+	runs := [][]string{{"-broken"}, nil, {"-broken"}, nil}
+	dirs := make([]string, len(runs))
+	var wg sync.WaitGroup
+	for i, args := range runs {
+		dirs[i] = t.TempDir()
+		if err := os.WriteFile(filepath.Join(dirs[i], "intact.rdp"), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			app.Run(append(args, dirs[i]))
+		}()
+	}
+	wg.Wait()
+
+	// A plain run after the others must not inherit their filters.
+	last := t.TempDir()
+	if err := os.WriteFile(filepath.Join(last, "intact.rdp"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	app.Run([]string{last})
+	runs, dirs = append(runs, nil), append(dirs, last)
+
+	for i, args := range runs {
+		_, err := os.Stat(filepath.Join(dirs[i], "intact.rdp"))
+		if kept := err == nil; kept != (len(args) > 0) {
+			t.Errorf("run %d with %q: file kept %v", i, args, kept)
+		}
+	}
+	if len(app.Deleter.Filters) != 0 {
+		t.Errorf("Application deleter has %d filters after the runs, want none", len(app.Deleter.Filters))
+	}
+}
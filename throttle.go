@@ -0,0 +1,60 @@
+package tasker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LoadThrottle holds deletions back while the host is under pressure and lets them resume at full
+// speed once the load drops. It is safe for concurrent use by the deletion workers.
+type LoadThrottle struct {
+	MaxLoad  float64
+	Interval time.Duration
+
+	mu        sync.Mutex
+	checked   time.Time
+	lastLoad  float64
+	throttled bool
+}
+
+// NewLoadThrottle creates a throttle pausing deletions while the per-CPU load average exceeds maxLoad.
+func NewLoadThrottle(maxLoad float64) *LoadThrottle {
+	return &LoadThrottle{MaxLoad: maxLoad, Interval: time.Second}
+}
+
+// Wait blocks while the system load exceeds MaxLoad. A nil throttle never blocks.
+func (lt *LoadThrottle) Wait() {
+	if lt == nil {
+		return
+	}
+	for lt.overloaded() {
+		time.Sleep(lt.Interval)
+	}
+}
+
+// overloaded samples the load at most once per interval, shared across all workers.
+func (lt *LoadThrottle) overloaded() bool {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	if time.Since(lt.checked) >= lt.Interval {
+		load, err := systemLoad()
+		if err != nil {
+			return false
+		}
+		lt.lastLoad = load
+		lt.checked = time.Now()
+	}
+
+	over := lt.lastLoad > lt.MaxLoad
+	if over != lt.throttled {
+		lt.throttled = over
+		if over {
+			fmt.Printf("System load %.2f per CPU exceeds %.2f, pausing deletions\n", lt.lastLoad, lt.MaxLoad)
+		} else {
+			fmt.Printf("System load %.2f per CPU, resuming deletions\n", lt.lastLoad)
+		}
+	}
+	return over
+}
@@ -0,0 +1,177 @@
+package tasker
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ByteSize is a size in bytes that accepts values such as 1024, 500MB or 1.5GiB on the command
+// line. Its String form parses back to the same size.
+type ByteSize int64
+
+// byteUnits lists the accepted size suffixes, largest first so String picks the biggest exact one.
+var byteUnits = []struct {
+	name string
+	size int64
+}{
+	{"PiB", 1 << 50}, {"PB", 1e15},
+	{"TiB", 1 << 40}, {"TB", 1e12},
+	{"GiB", 1 << 30}, {"GB", 1e9},
+	{"MiB", 1 << 20}, {"MB", 1e6},
+	{"KiB", 1 << 10}, {"KB", 1e3},
+	{"B", 1},
+}
+
+// ParseByteSize parses a non-negative size with an optional decimal or binary unit suffix. Units
+// are case-insensitive; fractions must come to a whole number of bytes.
+func ParseByteSize(s string) (ByteSize, error) {
+	num, unit := splitNumber(strings.TrimSpace(s))
+	value, ok := new(big.Rat).SetString(num)
+	if num == "" || !ok || value.Sign() < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+
+	multiplier := int64(1)
+	if unit != "" {
+		found := false
+		for _, u := range byteUnits {
+			if strings.EqualFold(unit, u.name) {
+				multiplier, found = u.size, true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("invalid size %q: unknown unit %q (use B, KB, KiB, MB, MiB, GB, GiB, TB, TiB, PB or PiB)", s, unit)
+		}
+	}
+
+	value.Mul(value, new(big.Rat).SetInt64(multiplier))
+	if !value.IsInt() {
+		return 0, fmt.Errorf("invalid size %q: not a whole number of bytes", s)
+	}
+	if !value.Num().IsInt64() {
+		return 0, fmt.Errorf("invalid size %q: too large", s)
+	}
+	return ByteSize(value.Num().Int64()), nil
+}
+
+// String formats the size with the largest unit that represents it exactly to three decimals.
+func (b ByteSize) String() string {
+	for _, u := range byteUnits {
+		if u.size > 1 && int64(b) >= u.size && int64(b) < 1<<53 && (int64(b)*1000)%u.size == 0 {
+			return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.3f", float64(b)/float64(u.size)), "0"), ".") + u.name
+		}
+	}
+	return fmt.Sprintf("%dB", int64(b))
+}
+
+// Set implements flag.Value.
+func (b *ByteSize) Set(s string) error {
+	size, err := ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+	*b = size
+	return nil
+}
+
+// Period is a duration that also accepts days (d), weeks (w), 30-day months (mo) and 365-day years
+// (y) on top of the units of time.ParseDuration, e.g. 36h, 2w or 1w3d. Its String form parses
+// back to the same duration.
+type Period time.Duration
+
+// periodUnits are the calendar units Period adds to time.ParseDuration.
+var periodUnits = map[string]time.Duration{
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+	"mo": 30 * 24 * time.Hour,
+	"y":  365 * 24 * time.Hour,
+}
+
+// ParsePeriod parses a non-negative period made of one or more number-unit pairs. A bare 0 is
+// accepted as zero.
+func ParsePeriod(s string) (Period, error) {
+	rest := strings.TrimSpace(s)
+	if rest == "0" {
+		return 0, nil
+	}
+	if rest == "" {
+		return 0, fmt.Errorf("invalid period %q", s)
+	}
+
+	var total time.Duration
+	for rest != "" {
+		num, tail := splitNumber(rest)
+		i := strings.IndexFunc(tail, func(r rune) bool { return unicode.IsDigit(r) || r == '.' })
+		if i < 0 {
+			i = len(tail)
+		}
+		unit := strings.TrimSpace(tail[:i])
+		rest = tail[i:]
+		if num == "" || unit == "" {
+			return 0, fmt.Errorf("invalid period %q: expected a number followed by a unit, e.g. 36h or 2w", s)
+		}
+
+		var part time.Duration
+		if size, ok := periodUnits[strings.ToLower(unit)]; ok {
+			value, ok := new(big.Rat).SetString(num)
+			if !ok {
+				return 0, fmt.Errorf("invalid period %q", s)
+			}
+			value.Mul(value, new(big.Rat).SetInt64(int64(size)))
+			ns := new(big.Int).Quo(value.Num(), value.Denom())
+			if !ns.IsInt64() {
+				return 0, fmt.Errorf("invalid period %q: too large", s)
+			}
+			part = time.Duration(ns.Int64())
+		} else {
+			d, err := time.ParseDuration(num + unit)
+			if err != nil {
+				return 0, fmt.Errorf("invalid period %q: unknown unit %q (use ns, us, ms, s, m, h, d, w, mo or y)", s, unit)
+			}
+			part = d
+		}
+		if part > math.MaxInt64-total {
+			return 0, fmt.Errorf("invalid period %q: too large", s)
+		}
+		total += part
+	}
+	return Period(total), nil
+}
+
+// String formats whole weeks or days with those units and anything else as time.Duration does.
+func (p Period) String() string {
+	d := time.Duration(p)
+	switch {
+	case d == 0:
+		return "0"
+	case d%periodUnits["w"] == 0:
+		return fmt.Sprintf("%dw", d/periodUnits["w"])
+	case d%periodUnits["d"] == 0:
+		return fmt.Sprintf("%dd", d/periodUnits["d"])
+	}
+	return d.String()
+}
+
+// Set implements flag.Value.
+func (p *Period) Set(s string) error {
+	period, err := ParsePeriod(s)
+	if err != nil {
+		return err
+	}
+	*p = period
+	return nil
+}
+
+// splitNumber splits a leading unsigned decimal number from the rest of s.
+func splitNumber(s string) (num, rest string) {
+	i := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], strings.TrimSpace(s[i:])
+}
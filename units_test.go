@@ -0,0 +1,47 @@
+package tasker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"0", 0, false},
+		{"36h", 36 * time.Hour, false},
+		{"1.5d", 36 * time.Hour, false},
+		{"1w3d", 10 * 24 * time.Hour, false},
+		{"106751d", 106751 * 24 * time.Hour, false},
+		{"300y", 0, true},
+		{"106752d", 0, true},
+		{"290y3000w", 0, true},
+		{"2x", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePeriod(tt.in)
+		if (err != nil) != tt.wantErr || time.Duration(got) != tt.want {
+			t.Errorf("ParsePeriod(%q) = %v, %v; want %v, error %v", tt.in, time.Duration(got), err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestByteDelta(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{0, "+0B"},
+		{1500, "+1.5KB"},
+		{-200, "-200B"},
+		{-3 << 20, "-3MiB"},
+	}
+	for _, tt := range tests {
+		if got := byteDelta(tt.in); got != tt.want {
+			t.Errorf("byteDelta(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}